@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// runBaseline implements "validator baseline create": it records every
+// current finding in a baseline file. Runs given the file with -baseline
+// then only fail on findings it does not list.
+func runBaseline(args []string) int {
+	if len(args) == 0 || args[0] != "create" {
+		fmt.Println("Usage: validator baseline create [-o file] [flags] <path>...")
+		return 2
+	}
+
+	flags := flag.NewFlagSet("baseline create", flag.ExitOnError)
+	out := flags.String("o", "", "baseline file to write (default: the config's baseline or fpvalidator-baseline.json)")
+	flags.StringVar(configPath, "config", "", "path to a YAML config file")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: validator baseline create [-o file] [flags] <path>...")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args[1:])
+	if err := applyEnv(flags); err != nil {
+		fmt.Println(err)
+		return 2
+	}
+
+	roots := flags.Args()
+	var cfg *validator.Config
+	if *configPath != "" {
+		var err error
+		if cfg, err = validator.LoadConfig(*configPath); err != nil {
+			fmt.Println(err)
+			return 1
+		}
+		roots = append(cfg.Roots, roots...)
+	}
+	if len(roots) == 0 {
+		flags.Usage()
+		return 2
+	}
+	if *out == "" {
+		*out = "fpvalidator-baseline.json"
+		if cfg != nil && cfg.Baseline != "" {
+			*out = cfg.Baseline
+		}
+	}
+
+	v, err := validator.New(validator.Options{Config: cfg})
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	defer closeValidator(v)
+
+	issues, err := v.Validate(roots...)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	if err := validator.NewBaseline(*out, issues).Save(*out); err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	fmt.Printf("Recorded %d findings in %s\n", len(issues), *out)
+	return 0
+}
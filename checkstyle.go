@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// The Checkstyle XML written by writeCheckstyle, as read by Jenkins
+// Warnings NG, reviewdog and other code-quality plugins.
+type (
+	checkstyleReport struct {
+		XMLName xml.Name         `xml:"checkstyle"`
+		Version string           `xml:"version,attr"`
+		Files   []checkstyleFile `xml:"file"`
+	}
+	checkstyleFile struct {
+		Name   string            `xml:"name,attr"`
+		Errors []checkstyleError `xml:"error"`
+	}
+	checkstyleError struct {
+		Line     int    `xml:"line,attr,omitempty"`
+		Column   int    `xml:"column,attr,omitempty"`
+		Severity string `xml:"severity,attr"`
+		Message  string `xml:"message,attr"`
+		Source   string `xml:"source,attr"`
+	}
+)
+
+// writeCheckstyle writes issues as a Checkstyle XML report, one file
+// element per file in the order the files were reported. The source of each
+// error is "fpvalidator." followed by the rule ID, or the rule name for
+// rules without one.
+func writeCheckstyle(w io.Writer, issues []validator.Issue) error {
+	report := checkstyleReport{Version: "8.0"}
+	index := make(map[string]int)
+	for _, e := range issues {
+		i, ok := index[e.Pos.Filename]
+		if !ok {
+			i = len(report.Files)
+			index[e.Pos.Filename] = i
+			report.Files = append(report.Files, checkstyleFile{Name: e.Pos.Filename})
+		}
+		source := e.ID
+		if source == "" {
+			source = e.Rule
+		}
+		report.Files[i].Errors = append(report.Files[i].Errors, checkstyleError{
+			Line:     e.Pos.Line,
+			Column:   e.Pos.Column,
+			Severity: e.Severity.String(),
+			Message:  e.Text(),
+			Source:   "fpvalidator." + source,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("writing Checkstyle report: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("writing Checkstyle report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,33 @@
+// Command fpvet runs the validator rules as go/analysis Analyzers. Build it
+// and hand it to go vet:
+//
+//	go build -o fpvet ./cmd/fpvet
+//	go vet -vettool=$(pwd)/fpvet [-config=fpvalidator.yaml] ./...
+//
+// Single rules can be selected with their Analyzer names, e.g. -getprefix.
+package main
+
+import (
+	"flag"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/analyzer"
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+	"golang.org/x/tools/go/analysis/multichecker"
+)
+
+func main() {
+	configPath := flag.String("config", "", "path to a YAML config file")
+	experimental := flag.Bool("include-experimental", false, "also run rules in the experimental stage")
+
+	multichecker.Main(analyzer.New(func() (*validator.Validator, error) {
+		opts := validator.Options{IncludeExperimental: *experimental}
+		if *configPath != "" {
+			cfg, err := validator.LoadConfig(*configPath)
+			if err != nil {
+				return nil, err
+			}
+			opts.Config = cfg
+		}
+		return validator.New(opts)
+	})...)
+}
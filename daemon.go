@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// defaultSocket is where "validator daemon" listens unless told otherwise.
+var defaultSocket = filepath.Join(os.TempDir(), fmt.Sprintf("fpvalidator-%d.sock", os.Getuid()))
+
+// daemonRequest asks the daemon to validate Paths, which are absolute.
+type daemonRequest struct {
+	Paths []string `json:"paths"`
+}
+
+// daemonLocalFlags are the flags a daemon cannot apply, since it validates
+// with the options it was started with.
+var daemonLocalFlags = []string{
+	"fix", "include", "exclude", "shard", "only-tags", "skip-tags", "dedupe",
+	"include-experimental", "clock-in-tests", "max-file-size", "max-memory",
+}
+
+// localFlag returns the first of daemonLocalFlags set on the command line or
+// in the environment, or "" when a daemon can serve the run.
+func localFlag() string {
+	var name string
+	flag.Visit(func(f *flag.Flag) {
+		if name == "" && slices.Contains(daemonLocalFlags, f.Name) {
+			name = f.Name
+		}
+	})
+	return name
+}
+
+// daemonResponse carries the issues of a daemonRequest, or why it failed.
+type daemonResponse struct {
+	Issues []validator.Issue `json:"issues"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// checker validates paths; it is a local *validator.Validator or a
+// daemonClient.
+type checker interface {
+	ValidateContext(ctx context.Context, paths ...string) ([]validator.Issue, error)
+}
+
+// runDaemon implements "validator daemon": it keeps a Validator, with its
+// plugins, WASM rules and the type information of imported packages, warm
+// in memory and serves validation requests on a unix socket, one JSON
+// request and response per connection. Editors and pre-commit hooks reach
+// it with "validator -daemon <socket> <path>".
+func runDaemon(args []string) int {
+	flags := flag.NewFlagSet("daemon", flag.ExitOnError)
+	socket := flags.String("socket", defaultSocket, "unix socket to listen on")
+	flags.StringVar(configPath, "config", "", "path to a YAML config file")
+	flags.StringVar(profile, "profile", "", "built-in rule profile the config is applied on top of")
+	flags.StringVar(pluginDir, "plugin-dir", "", "directory of Go plugins (.so) whose rules are run as well")
+	flags.BoolVar(clockInTests, "clock-in-tests", false, "also flag time.Now()/time.Since() inside _test.go files")
+	flags.BoolVar(experimental, "include-experimental", false, "also run rules in the experimental stage")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: validator daemon [-socket path] [flags]")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+	if err := applyEnv(flags); err != nil {
+		fmt.Println(err)
+		return 2
+	}
+
+	if *pluginDir != "" {
+		if err := validator.LoadGoPlugins(*pluginDir); err != nil {
+			fmt.Println(err)
+			return 1
+		}
+	}
+	var cfg *validator.Config
+	if *configPath != "" {
+		var err error
+		if cfg, err = validator.LoadConfig(*configPath); err != nil {
+			fmt.Println(err)
+			return 1
+		}
+	}
+	if *profile != "" {
+		var err error
+		if cfg, err = validator.ApplyProfile(cfg, *profile); err != nil {
+			fmt.Println(err)
+			return 1
+		}
+	}
+
+	v, err := validator.New(validator.Options{Config: cfg, ClockInTests: *clockInTests, IncludeExperimental: *experimental})
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	defer closeValidator(v)
+
+	// A socket left behind by a daemon that died is replaced; a live one is
+	// not.
+	if conn, err := net.Dial("unix", *socket); err == nil {
+		conn.Close()
+		fmt.Printf("a daemon is already listening on %s\n", *socket)
+		return 1
+	}
+	os.Remove(*socket)
+	ln, err := net.Listen("unix", *socket)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	defer os.Remove(*socket)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	fmt.Printf("Listening on %s\n", *socket)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0
+			}
+			fmt.Println(err)
+			return 1
+		}
+		go serveDaemonConn(ctx, v, conn)
+	}
+}
+
+// serveDaemonConn answers the single request of conn. The validation stops
+// when the client hangs up or ctx, the daemon's, is done.
+func serveDaemonConn(ctx context.Context, v *validator.Validator, conn net.Conn) {
+	defer conn.Close()
+
+	var req daemonRequest
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+
+	// Clients send nothing after the request, so a read returning means the
+	// client is gone.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		_, _ = conn.Read(make([]byte, 1))
+		cancel()
+	}()
+
+	var resp daemonResponse
+	v.Forget()
+	issues, err := v.ValidateContext(ctx, req.Paths...)
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	resp.Issues = issues
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+// daemonClient sends validation requests to a running "validator daemon".
+type daemonClient struct {
+	socket string
+}
+
+// dialDaemon returns a client for the daemon on socket, or an error when
+// none is listening there.
+func dialDaemon(socket string) (*daemonClient, error) {
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("no daemon on %s: %w", socket, err)
+	}
+	conn.Close()
+	return &daemonClient{socket: socket}, nil
+}
+
+// ValidateContext has the daemon validate paths. The issues name files as
+// the paths did, relative to the working directory where they were.
+func (c *daemonClient) ValidateContext(ctx context.Context, paths ...string) ([]validator.Issue, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	req := daemonRequest{}
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(wd, p)
+		}
+		req.Paths = append(req.Paths, p)
+	}
+
+	conn, err := net.Dial("unix", c.socket)
+	if err != nil {
+		return nil, fmt.Errorf("no daemon on %s: %w", c.socket, err)
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	var resp daemonResponse
+	if err := json.NewEncoder(conn).Encode(req); err == nil {
+		err = json.NewDecoder(conn).Decode(&resp)
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("daemon on %s: %w", c.socket, err)
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	for i, e := range resp.Issues {
+		if rel, err := filepath.Rel(wd, e.Pos.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			resp.Issues[i].Pos.Filename = rel
+		}
+	}
+	return resp.Issues, nil
+}
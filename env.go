@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// envPrefix starts the environment variables that set flags, e.g.
+// FPVALIDATOR_CONFIG for -config and FPVALIDATOR_FAIL_ON for -fail-on.
+const envPrefix = "FPVALIDATOR_"
+
+// envName returns the environment variable setting the named flag.
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// applyEnv sets every flag of flags not given on the command line from its
+// environment variable. Flags thus override the environment, which
+// overrides the config file, letting CI tweak a run without editing the
+// checked-in config.
+func applyEnv(flags *flag.FlagSet) error {
+	given := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+	var err error
+	flags.VisitAll(func(f *flag.Flag) {
+		value, ok := os.LookupEnv(envName(f.Name))
+		if !ok || given[f.Name] || err != nil {
+			return
+		}
+		if setErr := flags.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q for %s: %v", value, envName(f.Name), setErr)
+		}
+	})
+	return err
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// formats holds the machine-readable -format values. Each writes the
+// findings of every root as one document; the default "text" report is
+// printed by printReport instead.
+var formats = map[string]func(w io.Writer, issues []validator.Issue) error{
+	"checkstyle": writeCheckstyle,
+	"html":       writeHTML,
+	"json":       writeJSON,
+	"junit":      writeJUnit,
+	"markdown":   writeMarkdown,
+	"sarif":      writeSARIF,
+	"tap":        writeTAP,
+	"template":   writeTemplate,
+}
+
+// formatNames lists the accepted -format values.
+func formatNames() []string {
+	return append([]string{"text"}, slices.Sorted(maps.Keys(formats))...)
+}
+
+// writeReport validates every root and writes all their findings with write
+// to stdout, returning the exit code. Errors and interruptions go to stderr
+// so stdout stays parseable; an interrupted run still writes the findings
+// so far.
+func writeReport(ctx context.Context, v checker, baseline *validator.Baseline, roots []rootSet, failOn validator.Severity, write func(io.Writer, []validator.Issue) error) int {
+	var issues []validator.Issue
+	code := 0
+	for _, root := range roots {
+		errs, err := validateRoot(ctx, v, baseline, root)
+		issues = append(issues, errs...)
+		if ctx.Err() != nil {
+			fmt.Fprintf(os.Stderr, "Validation interrupted at %s; the report is incomplete\n", root)
+			code = 130
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", root, err)
+			code = 1
+		}
+	}
+	if err := write(os.Stdout, issues); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if code == 0 && failing(issues, failOn) {
+		code = 1
+	}
+	return code
+}
+
+// jsonIssue is the JSON form of a validator.Issue.
+type jsonIssue struct {
+	Rule       string     `json:"rule"`
+	ID         string     `json:"id,omitempty"`
+	Path       string     `json:"path"`
+	Line       int        `json:"line,omitempty"`
+	Column     int        `json:"column,omitempty"`
+	Severity   string     `json:"severity"`
+	Message    string     `json:"message"`
+	Suggestion string     `json:"suggestion,omitempty"`
+	Also       []string   `json:"also,omitempty"`
+	Fix        []jsonEdit `json:"fix,omitempty"`
+	Excerpt    string     `json:"excerpt,omitempty"`
+}
+
+// jsonEdit is the JSON form of a validator.TextEdit: replace the bytes from
+// Start to End with NewText.
+type jsonEdit struct {
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	NewText string `json:"newText,omitempty"`
+}
+
+// issue converts j back into the Issue it was written from.
+func (j jsonIssue) issue() (validator.Issue, error) {
+	var sev validator.Severity
+	if err := sev.UnmarshalText([]byte(j.Severity)); err != nil {
+		return validator.Issue{}, err
+	}
+	var fix []validator.TextEdit
+	for _, edit := range j.Fix {
+		fix = append(fix, validator.TextEdit{Start: edit.Start, End: edit.End, NewText: edit.NewText})
+	}
+	return validator.Issue{
+		Rule:       j.Rule,
+		ID:         j.ID,
+		Pos:        token.Position{Filename: j.Path, Line: j.Line, Column: j.Column},
+		Severity:   sev,
+		Message:    j.Message,
+		Suggestion: j.Suggestion,
+		Also:       j.Also,
+		Fix:        fix,
+	}, nil
+}
+
+// writeJSON writes issues as a JSON array, "[]" when there are none.
+func writeJSON(w io.Writer, issues []validator.Issue) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(jsonIssues(issues)); err != nil {
+		return fmt.Errorf("writing JSON report: %w", err)
+	}
+	return nil
+}
+
+// jsonIssues converts issues to their JSON form.
+func jsonIssues(issues []validator.Issue) []jsonIssue {
+	out := make([]jsonIssue, 0, len(issues))
+	for _, e := range issues {
+		line, _, _ := excerpt(e.Pos)
+		var fix []jsonEdit
+		for _, edit := range e.Fix {
+			fix = append(fix, jsonEdit{Start: edit.Start, End: edit.End, NewText: edit.NewText})
+		}
+		out = append(out, jsonIssue{
+			Rule:       e.Rule,
+			ID:         e.ID,
+			Path:       e.Pos.Filename,
+			Line:       e.Pos.Line,
+			Column:     e.Pos.Column,
+			Severity:   e.Severity.String(),
+			Message:    e.Message,
+			Suggestion: e.Suggestion,
+			Also:       e.Also,
+			Fix:        fix,
+			Excerpt:    line,
+		})
+	}
+	return out
+}
+
+// failing reports whether a finding in issues is at least as severe as
+// failOn; it never is when failOn is 0.
+func failing(issues []validator.Issue, failOn validator.Severity) bool {
+	return failOn != 0 && slices.ContainsFunc(issues, func(e validator.Issue) bool {
+		return e.Severity >= failOn
+	})
+}
@@ -1,3 +1,12 @@
 module github.com/ANISH-GOTTAPU/FPVALIDATOR
 
 go 1.24.2
+
+require (
+	github.com/tetratelabs/wazero v1.9.0
+	golang.org/x/mod v0.32.0
+	golang.org/x/tools v0.41.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sync v0.19.0 // indirect
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// htmlContext is the number of source lines shown around a finding in the
+// HTML report.
+const htmlContext = 2
+
+// The data the HTML report template is executed with.
+type (
+	htmlReport struct {
+		Headline string
+		Rules    []htmlRuleRow
+		Dirs     []htmlDirRow
+		Findings []htmlFinding
+	}
+	htmlRuleRow struct {
+		Rule, ID, Description string
+		Count                 int
+	}
+	htmlDirRow struct {
+		Dir                         string
+		Errors, Warnings, Info, All int
+	}
+	htmlFinding struct {
+		Path, Link     string
+		Line           int
+		Severity       string
+		Rule, ID, Text string
+		Snippet        []htmlLine
+	}
+	htmlLine struct {
+		Number int
+		Text   string
+		Hit    bool
+	}
+)
+
+// writeHTML writes issues as a single HTML page with no external assets,
+// for reviewers who do not run the validator: counts per rule and per
+// directory, then every finding with the source around it. Each table sorts
+// by the column whose header is clicked. With -link-base the locations link
+// to the source, as in the markdown report.
+func writeHTML(w io.Writer, issues []validator.Issue) error {
+	report := htmlReport{Headline: "all validation checks passed ✅"}
+	rules := make(map[string]*htmlRuleRow)
+	dirs := make(map[string]*htmlDirRow)
+	files := make(map[string]bool)
+	counts := make(map[validator.Severity]int)
+	for _, e := range issues {
+		counts[e.Severity]++
+		files[e.Pos.Filename] = true
+
+		rule, ok := rules[e.Rule]
+		if !ok {
+			rule = &htmlRuleRow{Rule: e.Rule, ID: e.ID}
+			if r, ok := validator.RuleFor(e.Rule); ok {
+				rule.Description = r.Description()
+			}
+			rules[e.Rule] = rule
+		}
+		rule.Count++
+
+		name := filepath.ToSlash(filepath.Dir(e.Pos.Filename))
+		dir, ok := dirs[name]
+		if !ok {
+			dir = &htmlDirRow{Dir: name}
+			dirs[name] = dir
+		}
+		dir.All++
+		switch e.Severity {
+		case validator.SeverityError:
+			dir.Errors++
+		case validator.SeverityWarning:
+			dir.Warnings++
+		default:
+			dir.Info++
+		}
+
+		path := filepath.ToSlash(e.Pos.Filename)
+		finding := htmlFinding{
+			Path:     path,
+			Line:     e.Pos.Line,
+			Severity: e.Severity.String(),
+			Rule:     e.Rule,
+			ID:       e.ID,
+			Text:     e.Text(),
+			Snippet:  htmlSnippet(e.Pos.Filename, e.Pos.Line),
+		}
+		if *linkBase != "" {
+			finding.Link = *linkBase + path
+			if e.Pos.Line > 0 {
+				finding.Link += fmt.Sprintf("#L%d", e.Pos.Line)
+			}
+		}
+		report.Findings = append(report.Findings, finding)
+	}
+
+	if len(issues) > 0 {
+		var parts []string
+		for _, sev := range []validator.Severity{validator.SeverityError, validator.SeverityWarning, validator.SeverityInfo} {
+			if n := counts[sev]; n > 0 {
+				parts = append(parts, plural(n, sev.String()))
+			}
+		}
+		report.Headline = fmt.Sprintf("%s in %s", strings.Join(parts, ", "), plural(len(files), "file"))
+	}
+	for _, rule := range rules {
+		report.Rules = append(report.Rules, *rule)
+	}
+	slices.SortFunc(report.Rules, func(a, b htmlRuleRow) int {
+		return cmp.Or(b.Count-a.Count, strings.Compare(a.Rule, b.Rule))
+	})
+	for _, dir := range dirs {
+		report.Dirs = append(report.Dirs, *dir)
+	}
+	slices.SortFunc(report.Dirs, func(a, b htmlDirRow) int {
+		return cmp.Or(b.All-a.All, strings.Compare(a.Dir, b.Dir))
+	})
+
+	b := bufio.NewWriter(w)
+	if err := htmlTemplate.Execute(b, report); err != nil {
+		return fmt.Errorf("writing HTML report: %w", err)
+	}
+	return flushReport(b, "HTML")
+}
+
+// htmlSnippet returns the lines of path around line, or nil when it has no
+// line or the file cannot be read.
+func htmlSnippet(path string, line int) []htmlLine {
+	lines := fileLines(path)
+	if line <= 0 || line > len(lines) {
+		return nil
+	}
+	var snippet []htmlLine
+	for n := max(1, line-htmlContext); n <= min(len(lines), line+htmlContext); n++ {
+		text := strings.TrimRight(lines[n-1], "\r")
+		if len(text) > maxExcerpt {
+			text = text[:maxExcerpt] + "…"
+		}
+		snippet = append(snippet, htmlLine{Number: n, Text: text, Hit: n == line})
+	}
+	return snippet
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>fpvalidator report</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #1f2328; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; cursor: pointer; user-select: none; }
+th.asc::after { content: " ▲"; }
+th.desc::after { content: " ▼"; }
+td.num { text-align: right; }
+.error { color: #cf222e; font-weight: bold; }
+.warning { color: #9a6700; font-weight: bold; }
+.info { color: #0969da; }
+pre { margin: 4px 0 0; font-size: 12px; background: #f6f8fa; padding: 4px; }
+pre .hit { background: #fff8c5; display: block; }
+pre .n { color: #6e7781; display: inline-block; width: 4em; }
+</style>
+</head>
+<body>
+<h1>fpvalidator: {{.Headline}}</h1>
+{{if .Findings}}
+<h2>By rule</h2>
+<table class="sortable">
+<thead><tr><th>Rule</th><th>ID</th><th>Description</th><th>Findings</th></tr></thead>
+<tbody>
+{{range .Rules}}<tr><td><code>{{.Rule}}</code></td><td>{{.ID}}</td><td>{{.Description}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</tbody>
+</table>
+<h2>By directory</h2>
+<table class="sortable">
+<thead><tr><th>Directory</th><th>Errors</th><th>Warnings</th><th>Info</th><th>Findings</th></tr></thead>
+<tbody>
+{{range .Dirs}}<tr><td><code>{{.Dir}}</code></td><td class="num">{{.Errors}}</td><td class="num">{{.Warnings}}</td><td class="num">{{.Info}}</td><td class="num">{{.All}}</td></tr>
+{{end}}</tbody>
+</table>
+<h2>Findings</h2>
+<table class="sortable">
+<thead><tr><th>File</th><th>Line</th><th>Severity</th><th>Rule</th><th>Finding</th></tr></thead>
+<tbody>
+{{range .Findings}}<tr>
+<td>{{if .Link}}<a href="{{.Link}}"><code>{{.Path}}</code></a>{{else}}<code>{{.Path}}</code>{{end}}</td>
+<td class="num">{{if .Line}}{{.Line}}{{end}}</td>
+<td class="{{.Severity}}">{{.Severity}}</td>
+<td>{{if .ID}}{{.ID}} {{end}}<code>{{.Rule}}</code></td>
+<td>{{.Text}}{{if .Snippet}}<pre>{{range .Snippet}}<span{{if .Hit}} class="hit"{{end}}><span class="n">{{.Number}}</span>{{.Text}}
+</span>{{end}}</pre>{{end}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+{{end}}
+<script>
+for (const table of document.querySelectorAll("table.sortable")) {
+  table.querySelectorAll("th").forEach((th, col) => th.addEventListener("click", () => {
+    const asc = !th.classList.contains("asc");
+    table.querySelectorAll("th").forEach(h => h.classList.remove("asc", "desc"));
+    th.classList.add(asc ? "asc" : "desc");
+    const body = table.tBodies[0];
+    const key = row => row.cells[col].firstChild ? row.cells[col].innerText.split("\n")[0] : "";
+    const rows = Array.from(body.rows).sort((a, b) => {
+      const x = key(a), y = key(b);
+      const n = x !== "" && y !== "" && !isNaN(x) && !isNaN(y) ? x - y : x.localeCompare(y);
+      return asc ? n : -n;
+    });
+    rows.forEach(row => body.appendChild(row));
+  }));
+}
+</script>
+</body>
+</html>
+`))
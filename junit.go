@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// The JUnit XML written by writeJUnit, in the form Jenkins and most other
+// CI systems read.
+type (
+	junitSuites struct {
+		XMLName xml.Name     `xml:"testsuites"`
+		Suites  []junitSuite `xml:"testsuite"`
+	}
+	junitSuite struct {
+		Name     string      `xml:"name,attr"`
+		Tests    int         `xml:"tests,attr"`
+		Failures int         `xml:"failures,attr"`
+		Cases    []junitCase `xml:"testcase"`
+	}
+	junitCase struct {
+		ClassName string        `xml:"classname,attr"`
+		Name      string        `xml:"name,attr"`
+		Failure   *junitFailure `xml:"failure,omitempty"`
+	}
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",cdata"`
+	}
+)
+
+// writeJUnit writes issues as a JUnit XML report with one failed test case
+// per rule and file: the class is the file, the test the rule, and the
+// failure lists every finding of the rule in the file. A clean run has a
+// single passing case, since CI systems reject reports without tests.
+func writeJUnit(w io.Writer, issues []validator.Issue) error {
+	suite := junitSuite{Name: "fpvalidator"}
+	index := make(map[[2]string]int)
+	worst := make(map[int]validator.Severity)
+	for _, e := range issues {
+		name := e.Rule
+		if e.ID != "" {
+			name = e.ID + " " + e.Rule
+		}
+		key := [2]string{e.Pos.Filename, name}
+		i, ok := index[key]
+		if !ok {
+			i = len(suite.Cases)
+			index[key] = i
+			suite.Cases = append(suite.Cases, junitCase{
+				ClassName: filepath.ToSlash(e.Pos.Filename),
+				Name:      name,
+				Failure:   &junitFailure{Message: e.Text()},
+			})
+		}
+		failure := suite.Cases[i].Failure
+		if failure.Text != "" {
+			failure.Message = fmt.Sprintf("%d findings", strings.Count(failure.Text, "\n")+1)
+		}
+		failure.Text += e.String() + "\n"
+		worst[i] = max(worst[i], e.Severity)
+		failure.Type = worst[i].String()
+	}
+	if len(suite.Cases) == 0 {
+		suite.Cases = []junitCase{{ClassName: "fpvalidator", Name: "all validation checks"}}
+	}
+	suite.Tests = len(suite.Cases)
+	suite.Failures = len(index)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("writing JUnit report: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return fmt.Errorf("writing JUnit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
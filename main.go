@@ -1,54 +1,500 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+var (
+	clockInTests = flag.Bool("clock-in-tests", false, "also flag time.Now()/time.Since() inside _test.go files")
+	configPath   = flag.String("config", "", "path to a YAML config file")
+	applyFixes   = flag.Bool("fix", false, "rewrite files with the automatic fixes offered by rules")
+	dedupe       = flag.Bool("dedupe", true, "fold findings of rules giving the same guidance at the same position")
+	pluginDir    = flag.String("plugin-dir", "", "directory of Go plugins (.so) whose rules are run as well")
+	profile      = flag.String("profile", "", "built-in rule profile the config is applied on top of: "+strings.Join(validator.Profiles(), ", "))
+	failOn       = flag.String("fail-on", "", "lowest severity that fails the run: error (default), warning, info or never")
+	noFail       = flag.Bool("no-fail", false, "report findings but always exit 0; same as -fail-on=never")
+	experimental = flag.Bool("include-experimental", false, "also run rules in the experimental stage")
+	onlyTags     = flag.String("only-tags", "", "comma-separated rule tags, e.g. gnmi,testing; only rules carrying one of them report")
+	skipTags     = flag.String("skip-tags", "", "comma-separated rule tags whose rules do not report")
+	verbose      = flag.Bool("v", false, "print notes about skipped files to stderr")
+	maxFileSize  = flag.Int64("max-file-size", 0, "largest .go file in bytes that is checked; larger ones are skipped with a warning (default 8 MiB or the config's maxFileSize)")
+	maxMemory    = flag.Int64("max-memory", 0, "memory budget in bytes; files are skipped with a warning while the heap stays over it (default none or the config's maxMemory)")
+	shardFlag    = flag.String("shard", "", "check only part N of M of the directories, e.g. 2/8, to split a run across parallel jobs; see validator merge")
+	linkBase     = flag.String("link-base", "", "URL prefix linking findings to their source in the markdown report, e.g. https://github.com/org/repo/blob/main/")
+	groupBy      = flag.String("group-by", "", "group the findings of the text report by rule, file or severity instead of listing them in the order found")
+	colorMode    = flag.String("color", "auto", "color findings and show the source line under each: auto (when stdout is a terminal), always or never")
+	showSnippets = flag.Bool("snippets", false, "show the source line and a caret under each finding of the text report even when it is not colored")
+	format       = flag.String("format", "text", "report format: "+strings.Join(formatNames(), ", "))
+	reviewerSum  = flag.Bool("reviewer-summary", false, "write a Markdown table of the findings per rule, with counts and one example each, for a review comment on a change with many findings")
+	templateText = flag.String("template", "", "Go template written for each finding with -format=template, e.g. '{{.File}}:{{.Line}} {{.RuleID}} {{.Message}}'; fields: File, Line, Column, Rule, RuleID, Severity, Message, Suggestion, Text, Also, Excerpt")
+	daemonSocket = flag.String("daemon", "", "unix socket of a running \"validator daemon\" to validate with; without one the run is local")
+	showStats    = flag.Bool("stats", false, "print counts per severity, rule and directory, the files checked and the time taken after the findings; -format=json reports them as a stats object")
+	baselinePath = flag.String("baseline", "", "baseline file of known findings; only findings it does not list are reported")
+	rootFlags    stringList
+	includeGlobs stringList
+	excludeGlobs stringList
+
+	// status receives the notes around the report, such as notices and
+	// baseline counts. Machine-readable formats send them to stderr so
+	// stdout holds only the report.
+	status io.Writer = os.Stdout
 )
 
+func init() {
+	flag.Var(&rootFlags, "root", "directory or file to validate; may be repeated")
+	flag.Var(&includeGlobs, "include", "glob of the files to validate, e.g. 'feature/**'; only files matching one are checked; may be repeated")
+	flag.Var(&excludeGlobs, "exclude", "glob of files to skip, e.g. 'internal/generated/**'; may be repeated")
+}
+
+// stringList is a flag.Value collecting every occurrence of a repeated flag.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 func main() {
-    if len(os.Args) < 2 {
-        fmt.Println("Usage: go run main.go <path>")
-        return
-    }
-
-    root := os.Args[1]
-    var errs []string
-
-    // Rule 20: check .proto files for full URL + bug ID
-    errs = append(errs, checkProtoFiles(root)...)
-
-    info, err := os.Stat(root)
-    if err != nil {
-        fmt.Println("Invalid path:", err)
-        return
-    }
-
-    if info.IsDir() {
-        _ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-            if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") {
-                return nil
-            }
-            validateGoFile(path, &errs)
-            return nil
-        })
-    } else {
-        if strings.HasSuffix(root, ".go") {
-            validateGoFile(root, &errs)
-        } else {
-            fmt.Println("Provided file is not a .go file")
-            return
-        }
-    }
-
-    if len(errs) > 0 {
-        fmt.Println("Validation failed:")
-        for _, e := range errs {
-            fmt.Println(" -", e)
-        }
-        os.Exit(1)
-    }
-    fmt.Println("All validation checks passed ✅")
+	os.Exit(run())
+}
+
+// run executes the validator and returns the process exit code.
+func run() int {
+	if len(os.Args) > 1 && os.Args[1] == "suppress" {
+		return runSuppress(os.Args[2:])
+	}
+	if len(os.Args) > 1 && os.Args[1] == "baseline" {
+		return runBaseline(os.Args[2:])
+	}
+	if len(os.Args) > 1 && os.Args[1] == "selfcheck" {
+		return runSelfcheck(os.Args[2:])
+	}
+	if len(os.Args) > 1 && os.Args[1] == "daemon" {
+		return runDaemon(os.Args[2:])
+	}
+	if len(os.Args) > 1 && os.Args[1] == "merge" {
+		return runMerge(os.Args[2:])
+	}
+
+	start := time.Now()
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: validator [flags] <path>...")
+		fmt.Fprintln(flag.CommandLine.Output(), "       validator suppress -rule=<rule> [flags] <path>")
+		fmt.Fprintln(flag.CommandLine.Output(), "       validator baseline create [-o file] [flags] <path>")
+		fmt.Fprintln(flag.CommandLine.Output(), "       validator selfcheck [-config file] [-profile name]")
+		fmt.Fprintln(flag.CommandLine.Output(), "       validator daemon [-socket path] [flags]")
+		fmt.Fprintln(flag.CommandLine.Output(), "       validator merge [-format f] [-o file] <report>...")
+		flag.PrintDefaults()
+		fmt.Fprintln(flag.CommandLine.Output(), "Every flag may also be set with an environment variable, e.g. FPVALIDATOR_FAIL_ON for")
+		fmt.Fprintln(flag.CommandLine.Output(), "-fail-on; flags take precedence over it, and it over the config file.")
+	}
+	args := parseInterspersed(flag.CommandLine, os.Args[1:])
+	if err := applyEnv(flag.CommandLine); err != nil {
+		fmt.Println(err)
+		return 2
+	}
+
+	// Go plugins register their rules before the config refers to them.
+	if *pluginDir != "" {
+		if err := validator.LoadGoPlugins(*pluginDir); err != nil {
+			fmt.Println(err)
+			return 1
+		}
+	}
+
+	var roots []rootSet
+	var cfg *validator.Config
+	if *configPath != "" {
+		var err error
+		if cfg, err = validator.LoadConfig(*configPath); err != nil {
+			fmt.Println(err)
+			return 1
+		}
+		for _, root := range cfg.Roots {
+			roots = append(roots, rootSet{root})
+		}
+	}
+	if *profile != "" {
+		var err error
+		if cfg, err = validator.ApplyProfile(cfg, *profile); err != nil {
+			fmt.Println(err)
+			return 1
+		}
+	}
+	for _, root := range rootFlags {
+		roots = append(roots, rootSet{root})
+	}
+
+	if len(roots) == 0 && len(args) == 0 {
+		flag.Usage()
+		return 0
+	}
+
+	if *failOn == "" && cfg != nil {
+		*failOn = cfg.FailOn
+	}
+	if *noFail {
+		*failOn = "never"
+	}
+	threshold, err := validator.ParseFailOn(*failOn)
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+	if styled, err = useStyle(*colorMode); err != nil {
+		fmt.Println(err)
+		return 2
+	}
+	snippets = *showSnippets
+	if _, ok := groupKeys[*groupBy]; !ok && *groupBy != "" {
+		fmt.Printf("unknown -group-by %q; want rule, file or severity\n", *groupBy)
+		return 2
+	}
+	shard, shards, err := parseShard(*shardFlag)
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+	write, structured := formats[*format]
+	if !structured && *format != "text" {
+		fmt.Printf("unknown -format %q; want one of %s\n", *format, strings.Join(formatNames(), ", "))
+		return 2
+	}
+	if *reviewerSum {
+		if structured {
+			fmt.Println("-reviewer-summary cannot be combined with -format")
+			return 2
+		}
+		write, structured = writeReviewerSummary, true
+	}
+	if err := parseTemplate(*templateText, *format); err != nil {
+		fmt.Println(err)
+		return 2
+	}
+	if structured {
+		status = os.Stderr
+	}
+	// The path arguments, e.g. the changed files of a CI run, are validated
+	// together as one root.
+	if paths := argumentPaths(args); len(paths) > 0 {
+		roots = append(roots, paths)
+	}
+	if len(roots) == 0 {
+		fmt.Fprintln(status, "No directories or .go files to validate")
+		return 0
+	}
+
+	var baseline *validator.Baseline
+	if *baselinePath == "" && cfg != nil {
+		*baselinePath = cfg.Baseline
+	}
+	if *baselinePath != "" {
+		var err error
+		if baseline, err = validator.LoadBaseline(*baselinePath); err != nil {
+			fmt.Println(err)
+			return 1
+		}
+	}
+
+	opts := validator.Options{
+		Config:         cfg,
+		ClockInTests:   *clockInTests,
+		Fix:            *applyFixes,
+		KeepDuplicates: !*dedupe,
+
+		IncludeExperimental: *experimental,
+		OnlyTags:            splitList(*onlyTags),
+		SkipTags:            splitList(*skipTags),
+
+		MaxFileSize: *maxFileSize,
+		MaxMemory:   *maxMemory,
+		Shard:       shard,
+		Shards:      shards,
+
+		Include: includeGlobs,
+		Exclude: excludeGlobs,
+	}
+	if *verbose {
+		opts.Verbose = os.Stderr
+	}
+	// A daemon, when one is running, validates with its own config and
+	// options; runs setting an option it cannot apply are local.
+	var v checker
+	if *daemonSocket != "" && localFlag() == "" {
+		client, err := dialDaemon(*daemonSocket)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v; validating locally\n", err)
+		} else {
+			v = client
+		}
+	}
+	if v == nil {
+		local, err := validator.New(opts)
+		if err != nil {
+			fmt.Println(err)
+			return 1
+		}
+		defer closeValidator(local)
+
+		for _, notice := range local.Notices() {
+			fmt.Fprintln(status, "Notice:", notice)
+		}
+		v = local
+	}
+
+	// An interrupt or a CI timeout stops the run after the current file; the
+	// findings so far are still printed.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if structured {
+		if *showStats {
+			plain := write
+			write = func(w io.Writer, issues []validator.Issue) error {
+				stats := newRunStats(issues, v, start)
+				if *format == "json" {
+					return writeJSONStats(w, issues, stats)
+				}
+				if err := plain(w, issues); err != nil {
+					return err
+				}
+				printStats(status, stats)
+				return nil
+			}
+		}
+		return writeReport(ctx, v, baseline, roots, threshold, write)
+	}
+
+	// A single root keeps the plain report; several roots get one section each.
+	if len(roots) == 1 {
+		errs, err := validateRoot(ctx, v, baseline, roots[0])
+		if ctx.Err() != nil {
+			printReport(errs, threshold)
+			fmt.Println("Validation interrupted; the report is incomplete")
+			return 130
+		}
+		if err != nil {
+			fmt.Println(err)
+			return 1
+		}
+		passed := printReport(errs, threshold)
+		if *showStats {
+			printStats(os.Stdout, newRunStats(errs, v, start))
+		}
+		if !passed {
+			return 1
+		}
+		return 0
+	}
+
+	failed := 0
+	var all []validator.Issue
+	for _, root := range roots {
+		fmt.Printf("=== %s ===\n", root)
+		errs, err := validateRoot(ctx, v, baseline, root)
+		all = append(all, errs...)
+		if ctx.Err() != nil {
+			printReport(errs, threshold)
+			fmt.Printf("\nValidation interrupted at %s; the report is incomplete\n", root)
+			return 130
+		}
+		if err != nil {
+			fmt.Println(err)
+			failed++
+		} else if !printReport(errs, threshold) {
+			failed++
+		}
+		fmt.Println()
+	}
+	if *showStats {
+		printStats(os.Stdout, newRunStats(all, v, start))
+		fmt.Println()
+	}
+
+	if failed > 0 {
+		fmt.Printf("Validation failed for %d of %d roots\n", failed, len(roots))
+		return 1
+	}
+	fmt.Printf("All %d roots passed validation ✅\n", len(roots))
+	return 0
+}
+
+// rootSet is what one section of the report covers: a -root or config root,
+// or every path given as an argument.
+type rootSet []string
+
+func (r rootSet) String() string {
+	return strings.Join(r, " ")
+}
+
+// parseInterspersed parses args with flags, letting flags follow the
+// positional arguments, as in "validator a b -format=json", and returns the
+// positional arguments.
+func parseInterspersed(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		_ = flags.Parse(args)
+		if flags.NArg() == 0 {
+			return positional
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
 }
 
+// argumentPaths returns the path arguments that can be validated. Lists of
+// changed files also name deleted files, noted on status, and other files
+// than .go files, dropped quietly; a path inside another listed directory
+// is dropped too, so its findings are not reported twice.
+func argumentPaths(args []string) rootSet {
+	var dirs, paths []string
+	for _, path := range args {
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			fmt.Fprintf(status, "Skipping %s: %v\n", path, errors.Unwrap(err))
+		case info.IsDir():
+			dirs = append(dirs, path)
+			paths = append(paths, path)
+		case strings.HasSuffix(path, ".go"):
+			paths = append(paths, path)
+		}
+	}
+
+	var kept rootSet
+	for _, path := range paths {
+		covered := slices.ContainsFunc(dirs, func(dir string) bool {
+			rel, err := filepath.Rel(dir, path)
+			return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+		})
+		if !covered && !slices.Contains(kept, path) {
+			kept = append(kept, path)
+		}
+	}
+	return kept
+}
+
+// closeValidator closes v, printing the errors of plugins that did not exit
+// cleanly.
+func closeValidator(v *validator.Validator) {
+	if err := v.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// validateRoot validates root and drops the findings recorded in baseline,
+// which may be nil. When ctx is done it returns the findings so far.
+func validateRoot(ctx context.Context, v checker, baseline *validator.Baseline, root rootSet) ([]validator.Issue, error) {
+	errs, err := v.ValidateContext(ctx, root...)
+	if (err != nil && ctx.Err() == nil) || baseline == nil {
+		return errs, err
+	}
+	errs, known := baseline.Filter(errs)
+	if known > 0 {
+		fmt.Fprintf(status, "Skipping %d findings recorded in the baseline\n", known)
+	}
+	return errs, err
+}
+
+// printReport prints the findings for one root and reports whether it passed:
+// it fails when a finding is at least as severe as failOn, and never when
+// failOn is 0.
+func printReport(errs []validator.Issue, failOn validator.Severity) bool {
+	if len(errs) == 0 {
+		fmt.Println("All validation checks passed ✅")
+		return true
+	}
+
+	worst := validator.SeverityInfo
+	for _, e := range errs {
+		worst = max(worst, e.Severity)
+	}
+	failed := failOn != 0 && worst >= failOn
+	switch {
+	case failed:
+		fmt.Println("Validation failed:")
+	case worst == validator.SeverityError:
+		fmt.Println("Validation found errors:")
+	case worst == validator.SeverityWarning:
+		fmt.Println("Validation passed with warnings:")
+	default:
+		fmt.Println("Validation passed with suggestions:")
+	}
+	key, grouped := groupKeys[*groupBy]
+	if !grouped {
+		for _, e := range errs {
+			printIssue(os.Stdout, e)
+		}
+		return !failed
+	}
+
+	groups := make(map[string][]validator.Issue)
+	var names []string
+	for _, e := range errs {
+		name := key(e)
+		if _, ok := groups[name]; !ok {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], e)
+	}
+	if *groupBy == "severity" {
+		sort.Slice(names, func(i, j int) bool {
+			return groups[names[i]][0].Severity > groups[names[j]][0].Severity
+		})
+	} else {
+		sort.Strings(names)
+	}
+	for _, name := range names {
+		fmt.Printf("\n%s (%d):\n", name, len(groups[name]))
+		for _, e := range groups[name] {
+			printIssue(os.Stdout, e)
+		}
+	}
+	return !failed
+}
+
+// groupKeys maps the -group-by values to the heading each finding is listed
+// under. Severities are listed most severe first, the others by name.
+var groupKeys = map[string]func(validator.Issue) string{
+	"rule": func(e validator.Issue) string {
+		if e.ID != "" {
+			return e.Rule + " [" + e.ID + "]"
+		}
+		return e.Rule
+	},
+	"file": func(e validator.Issue) string {
+		return e.Pos.Filename
+	},
+	"severity": func(e validator.Issue) string {
+		return e.Severity.String()
+	},
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// writeMarkdown writes issues as a Markdown summary for PR descriptions and
+// bot comments: a headline with the counts per severity, then a table of
+// findings per file. With -link-base the lines link to the source, e.g.
+// "https://github.com/org/repo/blob/<sha>/" + path + "#L42".
+func writeMarkdown(w io.Writer, issues []validator.Issue) error {
+	b := bufio.NewWriter(w)
+	if len(issues) == 0 {
+		fmt.Fprintln(b, "### fpvalidator: all validation checks passed ✅")
+		return flushReport(b, "Markdown")
+	}
+
+	counts := make(map[validator.Severity]int)
+	var files []string
+	byFile := make(map[string][]validator.Issue)
+	for _, e := range issues {
+		counts[e.Severity]++
+		if _, ok := byFile[e.Pos.Filename]; !ok {
+			files = append(files, e.Pos.Filename)
+		}
+		byFile[e.Pos.Filename] = append(byFile[e.Pos.Filename], e)
+	}
+	var parts []string
+	for _, sev := range []validator.Severity{validator.SeverityError, validator.SeverityWarning, validator.SeverityInfo} {
+		if n := counts[sev]; n > 0 {
+			parts = append(parts, plural(n, sev.String()))
+		}
+	}
+	fmt.Fprintf(b, "### fpvalidator: %s in %s\n", strings.Join(parts, ", "), plural(len(files), "file"))
+
+	for _, file := range files {
+		fmt.Fprintf(b, "\n#### `%s`\n\n", filepath.ToSlash(file))
+		fmt.Fprintln(b, "| Line | Severity | Rule | Finding |")
+		fmt.Fprintln(b, "| ---: | --- | --- | --- |")
+		for _, e := range byFile[file] {
+			line := "-"
+			if e.Pos.Line > 0 {
+				line = fmt.Sprint(e.Pos.Line)
+				if *linkBase != "" {
+					line = fmt.Sprintf("[%d](%s%s#L%d)", e.Pos.Line, *linkBase, filepath.ToSlash(file), e.Pos.Line)
+				}
+			}
+			rule := "`" + e.Rule + "`"
+			if e.ID != "" {
+				rule = e.ID + " " + rule
+			}
+			fmt.Fprintf(b, "| %s | %s | %s | %s |\n", line, e.Severity, rule, markdownCell(e.Text()))
+		}
+	}
+	return flushReport(b, "Markdown")
+}
+
+// markdownCell escapes s for a Markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// plural returns "1 error", "2 errors" and the like.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// flushReport flushes b, naming the format in the error.
+func flushReport(b *bufio.Writer, format string) error {
+	if err := b.Flush(); err != nil {
+		return fmt.Errorf("writing %s report: %w", format, err)
+	}
+	return nil
+}
+
+// writeReviewerSummary writes issues as a Markdown table for a review
+// comment on a change with too many findings to list: one row per rule,
+// most findings first, with the count, the worst severity and the first
+// finding as an example.
+func writeReviewerSummary(w io.Writer, issues []validator.Issue) error {
+	b := bufio.NewWriter(w)
+	if len(issues) == 0 {
+		fmt.Fprintln(b, "### fpvalidator: all validation checks passed ✅")
+		return flushReport(b, "reviewer summary")
+	}
+
+	type ruleSummary struct {
+		example validator.Issue
+		count   int
+		worst   validator.Severity
+	}
+	var rules []*ruleSummary
+	byRule := make(map[string]*ruleSummary)
+	files := make(map[string]bool)
+	for _, e := range issues {
+		files[e.Pos.Filename] = true
+		r, ok := byRule[e.Rule]
+		if !ok {
+			r = &ruleSummary{example: e}
+			byRule[e.Rule] = r
+			rules = append(rules, r)
+		}
+		r.count++
+		r.worst = max(r.worst, e.Severity)
+	}
+	slices.SortStableFunc(rules, func(a, b *ruleSummary) int {
+		return b.count - a.count
+	})
+
+	fmt.Fprintf(b, "### fpvalidator: %s from %s in %s\n\n", plural(len(issues), "finding"), plural(len(rules), "rule"), plural(len(files), "file"))
+	fmt.Fprintln(b, "| Rule | Severity | Findings | Example |")
+	fmt.Fprintln(b, "| --- | --- | ---: | --- |")
+	for _, r := range rules {
+		e := r.example
+		rule := "`" + e.Rule + "`"
+		if e.ID != "" {
+			rule = e.ID + " " + rule
+		}
+		loc := filepath.ToSlash(e.Pos.Filename)
+		if e.Pos.Line > 0 {
+			loc += fmt.Sprintf(":%d", e.Pos.Line)
+		}
+		loc = "`" + loc + "`"
+		if *linkBase != "" {
+			link := *linkBase + filepath.ToSlash(e.Pos.Filename)
+			if e.Pos.Line > 0 {
+				link += fmt.Sprintf("#L%d", e.Pos.Line)
+			}
+			loc = fmt.Sprintf("[%s](%s)", loc, link)
+		}
+		fmt.Fprintf(b, "| %s | %s | %d | %s: %s |\n", rule, r.worst, r.count, loc, markdownCell(e.Text()))
+	}
+	fmt.Fprintln(b, "\nRun `validator -group-by=rule` on the change for every finding.")
+	return flushReport(b, "reviewer summary")
+}
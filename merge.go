@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// parseShard parses a -shard value such as "2/8", the second of eight jobs,
+// into its 1-based index and count. "" means an unsharded run.
+func parseShard(s string) (shard, shards int, err error) {
+	if s == "" {
+		return 0, 0, nil
+	}
+	n, m, ok := strings.Cut(s, "/")
+	shard, errN := strconv.Atoi(n)
+	shards, errM := strconv.Atoi(m)
+	if !ok || errN != nil || errM != nil || shards < 1 || shard < 1 || shard > shards {
+		return 0, 0, fmt.Errorf("invalid -shard %q; want N/M with 1 <= N <= M, e.g. 2/8", s)
+	}
+	return shard, shards, nil
+}
+
+// mergeFormats maps the extensions of -o files to the format written to
+// them when -format is not given.
+var mergeFormats = map[string]string{
+	".html":  "html",
+	".json":  "json",
+	".sarif": "sarif",
+	".md":    "markdown",
+	".tap":   "tap",
+}
+
+// runMerge implements "validator merge": it combines the JSON or SARIF
+// reports of the jobs of a sharded run, or of per-module runs, into one
+// report and exits like a single run over everything would.
+func runMerge(args []string) int {
+	flags := flag.NewFlagSet("merge", flag.ExitOnError)
+	out := flags.String("format", "json", "format of the merged report: "+strings.Join(formatNames(), ", "))
+	output := flags.String("o", "", "file to write the merged report to instead of stdout; its extension sets the format unless -format is given")
+	flags.StringVar(failOn, "fail-on", "", "lowest severity that fails the run: error (default), warning, info or never")
+	flags.StringVar(templateText, "template", "", "Go template written for each finding with -format=template")
+	flags.BoolVar(reviewerSum, "reviewer-summary", false, "write a Markdown table of the findings per rule, with counts and one example each")
+	flags.StringVar(groupBy, "group-by", "", "group the findings of the text report by rule, file or severity")
+	flags.StringVar(linkBase, "link-base", "", "URL prefix linking findings to their source in the markdown report")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: validator merge [-format f] [-o file] [-fail-on s] <report>...")
+		flags.PrintDefaults()
+	}
+	// Flags may follow the reports, as in "merge a.json b.json -o c.sarif".
+	paths := parseInterspersed(flags, args)
+	if err := applyEnv(flags); err != nil {
+		fmt.Println(err)
+		return 2
+	}
+	if len(paths) == 0 {
+		flags.Usage()
+		return 2
+	}
+	threshold, err := validator.ParseFailOn(*failOn)
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+	given := false
+	flags.Visit(func(f *flag.Flag) {
+		given = given || f.Name == "format"
+	})
+	if *reviewerSum && given {
+		fmt.Println("-reviewer-summary cannot be combined with -format")
+		return 2
+	}
+	if *output != "" && !given && !*reviewerSum {
+		ext := strings.ToLower(filepath.Ext(*output))
+		if *out = mergeFormats[ext]; *out == "" {
+			fmt.Printf("cannot tell the report format of %s from its extension; set -format\n", *output)
+			return 2
+		}
+	}
+	write, structured := formats[*out]
+	if !structured && *out != "text" {
+		fmt.Printf("unknown -format %q; want one of %s\n", *out, strings.Join(formatNames(), ", "))
+		return 2
+	}
+	if *reviewerSum {
+		write, structured = writeReviewerSummary, true
+	}
+	if !structured && *output != "" {
+		fmt.Println("-o needs a -format other than text")
+		return 2
+	}
+
+	var issues []validator.Issue
+	type key struct {
+		rule, path, text string
+		line, column     int
+	}
+	seen := make(map[key]bool)
+	for _, path := range paths {
+		report, err := readReport(path)
+		if err != nil {
+			fmt.Printf("reading %s: %v\n", path, err)
+			return 1
+		}
+		for _, e := range report {
+			// Shards overlap only when their roots do; per-module runs may
+			// share files, and a SARIF report repeats a JSON one.
+			k := key{e.Rule, filepath.ToSlash(filepath.Clean(e.Pos.Filename)), e.Text(), e.Pos.Line, e.Pos.Column}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			issues = append(issues, e)
+		}
+	}
+	// Shards and modules interleave once merged.
+	validator.SortIssues(issues)
+
+	if _, ok := groupKeys[*groupBy]; !ok && *groupBy != "" {
+		fmt.Printf("unknown -group-by %q; want rule, file or severity\n", *groupBy)
+		return 2
+	}
+	if err := parseTemplate(*templateText, *out); err != nil {
+		fmt.Println(err)
+		return 2
+	}
+
+	if !structured {
+		styled, _ = useStyle("auto")
+		if !printReport(issues, threshold) {
+			return 1
+		}
+		return 0
+	}
+	if *output == "" {
+		err = write(os.Stdout, issues)
+	} else {
+		err = writeFile(*output, issues, write)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if failing(issues, threshold) {
+		return 1
+	}
+	return 0
+}
+
+// writeFile writes issues to the file at path with write.
+func writeFile(path string, issues []validator.Issue, write func(io.Writer, []validator.Issue) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f, issues); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readReport reads the findings of a -format=json report, with or without
+// -stats, or of a -format=sarif report.
+func readReport(path string) ([]validator.Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report []jsonIssue
+	switch trimmed := bytes.TrimSpace(data); {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, err
+		}
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var log struct {
+			sarifLog
+			Findings []jsonIssue `json:"findings"`
+		}
+		if err := json.Unmarshal(data, &log); err != nil {
+			return nil, err
+		}
+		if log.Version != "" {
+			return sarifIssues(log.sarifLog), nil
+		}
+		if log.Findings == nil {
+			return nil, fmt.Errorf("not a SARIF log or JSON report")
+		}
+		report = log.Findings
+	default:
+		return nil, fmt.Errorf("not a JSON or SARIF report")
+	}
+
+	issues := make([]validator.Issue, 0, len(report))
+	for _, j := range report {
+		e, err := j.issue()
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, e)
+	}
+	return issues, nil
+}
+
+// sarifIssues converts the results of a SARIF log back into findings. The
+// message keeps the suggestion a SARIF result text includes.
+func sarifIssues(log sarifLog) []validator.Issue {
+	var issues []validator.Issue
+	for _, run := range log.Runs {
+		for _, r := range run.Results {
+			e := validator.Issue{Rule: r.RuleID, Message: r.Message.Text}
+			if r.RuleIndex >= 0 && r.RuleIndex < len(run.Tool.Driver.Rules) && run.Tool.Driver.Rules[r.RuleIndex].Name != "" {
+				e.Rule = run.Tool.Driver.Rules[r.RuleIndex].Name
+			}
+			if id := validator.RuleID(e.Rule); id == r.RuleID {
+				e.ID = id
+			}
+			switch r.Level {
+			case "error":
+				e.Severity = validator.SeverityError
+			case "warning":
+				e.Severity = validator.SeverityWarning
+			default:
+				e.Severity = validator.SeverityInfo
+			}
+			if len(r.Locations) > 0 {
+				location := r.Locations[0].PhysicalLocation
+				e.Pos.Filename = sarifPath(location.ArtifactLocation)
+				if location.Region != nil {
+					e.Pos.Line, e.Pos.Column = location.Region.StartLine, location.Region.StartColumn
+				}
+			}
+			for _, fix := range r.Fixes {
+				for _, change := range fix.ArtifactChanges {
+					for _, rep := range change.Replacements {
+						if rep.DeletedRegion.ByteOffset == nil {
+							continue
+						}
+						edit := validator.TextEdit{Start: *rep.DeletedRegion.ByteOffset, End: *rep.DeletedRegion.ByteOffset}
+						if rep.DeletedRegion.ByteLength != nil {
+							edit.End += *rep.DeletedRegion.ByteLength
+						}
+						if rep.InsertedContent != nil {
+							edit.NewText = rep.InsertedContent.Text
+						}
+						e.Fix = append(e.Fix, edit)
+					}
+				}
+			}
+			issues = append(issues, e)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,147 @@
+// Package analyzer exposes the validator rules as go/analysis Analyzers, so
+// they run under go vet -vettool and the other standard analysis drivers.
+//
+// Every registered validator.Rule becomes one Analyzer, named after the rule
+// ID without dashes ("get-prefix" gives "getprefix"), and the checks spanning
+// a package form the "packagechecks" Analyzer. Rules offering a fix, such as
+// unkeyed-literal, attach it as a SuggestedFix. The rules look at one file
+// at a time and need no facts; rules declaring validator.NeedsTypes reuse the
+// type information of the pass.
+package analyzer
+
+import (
+	"go/token"
+	"strings"
+	"sync"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+	"golang.org/x/tools/go/analysis"
+)
+
+// New returns an Analyzer for every registered rule and one for the package
+// checks. newValidator is called once, when the first Analyzer runs, and
+// builds the Validator whose config decides scoping and suppressions.
+func New(newValidator func() (*validator.Validator, error)) []*analysis.Analyzer {
+	shared := &sharedValidator{build: newValidator}
+
+	var analyzers []*analysis.Analyzer
+	for _, r := range validator.Rules() {
+		analyzers = append(analyzers, &analysis.Analyzer{
+			Name: Name(r.ID()),
+			Doc:  validator.RuleDocs(r),
+			URL:  "https://github.com/ANISH-GOTTAPU/FPVALIDATOR",
+			Run: func(pass *analysis.Pass) (any, error) {
+				v, err := shared.get()
+				if err != nil {
+					return nil, err
+				}
+				typed := validator.RuleRequirements(r)&validator.NeedsTypes != 0
+				for _, f := range pass.Files {
+					path := pass.Fset.File(f.Pos()).Name()
+					if typed {
+						reportIssues(pass, v.CheckTypedFile(r, path, pass.Fset, f, pass.TypesInfo))
+					} else {
+						reportIssues(pass, v.CheckFile(r, path, pass.Fset, f))
+					}
+				}
+				return nil, nil
+			},
+		})
+	}
+
+	analyzers = append(analyzers, &analysis.Analyzer{
+		Name: "packagechecks",
+		Doc:  "Checks spanning the files of a package: TestMain, duplicate functions, import aliases and package docs.",
+		URL:  "https://github.com/ANISH-GOTTAPU/FPVALIDATOR",
+		Run: func(pass *analysis.Pass) (any, error) {
+			v, err := shared.get()
+			if err != nil {
+				return nil, err
+			}
+			var paths []string
+			for _, f := range pass.Files {
+				paths = append(paths, pass.Fset.File(f.Pos()).Name())
+			}
+			reportIssues(pass, v.CheckPackage(paths))
+			return nil, nil
+		},
+	})
+	return analyzers
+}
+
+// Name turns a rule ID into a valid Analyzer name.
+func Name(ruleID string) string {
+	return strings.ReplaceAll(ruleID, "-", "")
+}
+
+// sharedValidator builds the Validator on first use, once the driver has
+// parsed its flags.
+type sharedValidator struct {
+	build func() (*validator.Validator, error)
+	once  sync.Once
+	v     *validator.Validator
+	err   error
+}
+
+func (s *sharedValidator) get() (*validator.Validator, error) {
+	s.once.Do(func() {
+		s.v, s.err = s.build()
+	})
+	return s.v, s.err
+}
+
+// reportIssues hands issues to the driver. Issues in files outside the pass,
+// which the drivers cannot locate, are dropped.
+func reportIssues(pass *analysis.Pass, issues []validator.Issue) {
+	for _, issue := range issues {
+		tf := passFile(pass, issue.Pos.Filename)
+		if tf == nil {
+			continue
+		}
+
+		d := analysis.Diagnostic{
+			Pos:      position(tf, issue.Pos),
+			Category: issue.Rule,
+			Message:  issue.Text(),
+		}
+		if issue.ID != "" {
+			d.Message = "[" + issue.ID + "] " + d.Message
+		}
+		if len(issue.Fix) > 0 {
+			fix := analysis.SuggestedFix{Message: "Apply the fix for " + issue.Rule}
+			for _, e := range issue.Fix {
+				fix.TextEdits = append(fix.TextEdits, analysis.TextEdit{
+					Pos:     tf.Pos(e.Start),
+					End:     tf.Pos(e.End),
+					NewText: []byte(e.NewText),
+				})
+			}
+			d.SuggestedFixes = []analysis.SuggestedFix{fix}
+		}
+		pass.Report(d)
+	}
+}
+
+// passFile returns the file of the pass named filename, or nil.
+func passFile(pass *analysis.Pass, filename string) *token.File {
+	for _, f := range pass.Files {
+		if tf := pass.Fset.File(f.Pos()); tf != nil && tf.Name() == filename {
+			return tf
+		}
+	}
+	return nil
+}
+
+// position converts a line and column in tf to a token.Pos. Issues without
+// a line point at the start of the file.
+func position(tf *token.File, pos token.Position) token.Pos {
+	if pos.Line <= 0 || pos.Line > tf.LineCount() {
+		return tf.Pos(0)
+	}
+	p := tf.LineStart(pos.Line)
+	if pos.Column > 1 {
+		offset := min(tf.Offset(p)+pos.Column-1, tf.Size())
+		p = tf.Pos(offset)
+	}
+	return p
+}
@@ -0,0 +1,133 @@
+// Package rpcplugin lets teams ship validation rules as separate binaries.
+//
+// A plugin is an executable that calls Serve with its Rule. The validator
+// starts every plugin listed in its config as a subprocess and talks to it
+// using JSON-RPC over the subprocess stdin/stdout, so plugins must write any
+// logging to stderr.
+package rpcplugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+)
+
+// serviceName is the RPC service every plugin registers.
+const serviceName = "Plugin"
+
+// Finding is a single violation reported by a plugin rule.
+type Finding struct {
+	Line    int
+	Message string
+
+	// Suggestion optionally says how to resolve the violation.
+	Suggestion string
+}
+
+// Rule is implemented by plugin binaries.
+type Rule interface {
+	// Check inspects one Go source file and returns its violations.
+	Check(path string, src []byte) ([]Finding, error)
+}
+
+// CheckArgs is the request sent to a plugin for each file.
+type CheckArgs struct {
+	Path   string
+	Source []byte
+}
+
+// CheckReply is the plugin response for a single file.
+type CheckReply struct {
+	Findings []Finding
+}
+
+// service adapts a Rule to the net/rpc method signature.
+type service struct {
+	rule Rule
+}
+
+// Check is the RPC entry point invoked by the validator.
+func (s *service) Check(args *CheckArgs, reply *CheckReply) error {
+	findings, err := s.rule.Check(args.Path, args.Source)
+	if err != nil {
+		return err
+	}
+	reply.Findings = findings
+	return nil
+}
+
+// stdio joins the process stdin and stdout into a single connection.
+type stdio struct {
+	io.Reader
+	io.WriteCloser
+}
+
+// Serve runs rule as a plugin, answering requests on stdin/stdout until the
+// validator closes the connection. It is meant to be called from main.
+func Serve(rule Rule) {
+	server := rpc.NewServer()
+	if err := server.RegisterName(serviceName, &service{rule: rule}); err != nil {
+		fmt.Fprintln(os.Stderr, "rpcplugin:", err)
+		os.Exit(1)
+	}
+	server.ServeCodec(jsonrpc.NewServerCodec(stdio{os.Stdin, os.Stdout}))
+}
+
+// Client is a running plugin subprocess.
+type Client struct {
+	Name string
+	cmd  *exec.Cmd
+	rpc  *rpc.Client
+}
+
+// Start launches the plugin binary at path and connects to it.
+func Start(name, path string, args ...string) (*Client, error) {
+	cmd := exec.Command(path, args...)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", name, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", name, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting plugin %s: %w", name, err)
+	}
+
+	return &Client{
+		Name: name,
+		cmd:  cmd,
+		rpc:  jsonrpc.NewClient(stdio{stdout, stdin}),
+	}, nil
+}
+
+// Check sends one file to the plugin and returns its findings. When ctx is
+// done before the plugin answers, the plugin is killed, since it may never
+// answer, and c can no longer be used.
+func (c *Client) Check(ctx context.Context, path string, src []byte) ([]Finding, error) {
+	reply := &CheckReply{}
+	call := c.rpc.Go(serviceName+".Check", &CheckArgs{Path: path, Source: src}, reply, nil)
+	select {
+	case <-call.Done:
+		if call.Error != nil {
+			return nil, fmt.Errorf("plugin %s: %w", c.Name, call.Error)
+		}
+		return reply.Findings, nil
+	case <-ctx.Done():
+		_ = c.cmd.Process.Kill()
+		return nil, fmt.Errorf("plugin %s: %w", c.Name, ctx.Err())
+	}
+}
+
+// Close shuts the connection down and waits for the plugin to exit.
+func (c *Client) Close() error {
+	c.rpc.Close()
+	return c.cmd.Wait()
+}
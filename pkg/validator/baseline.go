@@ -0,0 +1,132 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// baselineVersion is the format version written to baseline files.
+const baselineVersion = 1
+
+// Baseline records the findings a codebase already has, so that only new
+// ones fail validation. Findings are matched by file, rule and message but
+// not by line, so edits elsewhere in a file do not invalidate them; a file
+// with N recorded findings of a kind may keep up to N of them.
+type Baseline struct {
+	// dir is the directory file paths are relative to.
+	dir     string
+	entries map[baselineKey]int
+}
+
+// baselineKey identifies a kind of finding in a file.
+type baselineKey struct {
+	File    string `json:"file"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// baselineEntry is a line of the baseline file.
+type baselineEntry struct {
+	baselineKey
+	Count int `json:"count"`
+}
+
+// baselineFile is the JSON document stored on disk.
+type baselineFile struct {
+	Version  int             `json:"version"`
+	Findings []baselineEntry `json:"findings"`
+}
+
+// NewBaseline returns a baseline holding issues, for saving next to path.
+func NewBaseline(path string, issues []Issue) *Baseline {
+	b := &Baseline{dir: filepath.Dir(path), entries: make(map[baselineKey]int)}
+	for _, issue := range issues {
+		b.entries[b.key(issue)]++
+	}
+	return b
+}
+
+// LoadBaseline reads the baseline file at path.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline %s: %w", path, err)
+	}
+	var file baselineFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", path, err)
+	}
+	if file.Version != baselineVersion {
+		return nil, fmt.Errorf("parsing baseline %s: unsupported version %d", path, file.Version)
+	}
+
+	b := &Baseline{dir: filepath.Dir(path), entries: make(map[baselineKey]int)}
+	for _, e := range file.Findings {
+		b.entries[e.baselineKey] += e.Count
+	}
+	return b, nil
+}
+
+// Save writes b to path, sorted so that diffs of the file stay readable.
+func (b *Baseline) Save(path string) error {
+	file := baselineFile{Version: baselineVersion}
+	for key, count := range b.entries {
+		file.Findings = append(file.Findings, baselineEntry{baselineKey: key, Count: count})
+	}
+	sort.Slice(file.Findings, func(i, j int) bool {
+		a, c := file.Findings[i], file.Findings[j]
+		if a.File != c.File {
+			return a.File < c.File
+		}
+		if a.Rule != c.Rule {
+			return a.Rule < c.Rule
+		}
+		return a.Message < c.Message
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Filter returns the issues not covered by the baseline and the number of
+// issues it covered.
+func (b *Baseline) Filter(issues []Issue) (fresh []Issue, known int) {
+	left := make(map[baselineKey]int, len(b.entries))
+	for key, count := range b.entries {
+		left[key] = count
+	}
+	for _, issue := range issues {
+		key := b.key(issue)
+		if left[key] > 0 {
+			left[key]--
+			known++
+			continue
+		}
+		fresh = append(fresh, issue)
+	}
+	return fresh, known
+}
+
+// key returns the baseline key of issue. Files are stored relative to the
+// baseline and rules by ID where they have one.
+func (b *Baseline) key(issue Issue) baselineKey {
+	file := issue.Pos.Filename
+	if abs, err := filepath.Abs(file); err == nil {
+		if dir, err := filepath.Abs(b.dir); err == nil {
+			if rel, err := filepath.Rel(dir, abs); err == nil {
+				file = rel
+			}
+		}
+	}
+	rule := issue.Rule
+	if id := RuleID(rule); id != "" {
+		rule = id
+	}
+	return baselineKey{File: filepath.ToSlash(file), Rule: rule, Message: issue.Text()}
+}
@@ -0,0 +1,414 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Config is the optional YAML configuration, read with LoadConfig.
+type Config struct {
+	// Roots lists directory roots validated in a single invocation. Relative
+	// entries are resolved against the directory holding the config file.
+	Roots []string `yaml:"roots"`
+
+	// Profile names a built-in profile, e.g. "minimal", the rest of the
+	// config is applied on top of.
+	Profile string `yaml:"profile"`
+
+	// Enable turns on opt-in rules by name, e.g. "function-order".
+	Enable []string `yaml:"enable"`
+
+	// Disable turns off rules by name, e.g. "magic-number". It accepts the
+	// IDs of registered rules as well as the rule names issues carry.
+	Disable []string `yaml:"disable"`
+
+	// OnlyTags and SkipTags select rules by tag like the -only-tags and
+	// -skip-tags flags, which add to them.
+	OnlyTags []string `yaml:"onlyTags"`
+	SkipTags []string `yaml:"skipTags"`
+
+	// Acronyms extends the acronyms identifiers must case as written,
+	// e.g. "gNMI" or "BGP".
+	Acronyms []string `yaml:"acronyms"`
+
+	// GetPrefix configures exemptions from the Get-prefix ban.
+	GetPrefix GetPrefixConfig `yaml:"getPrefix"`
+
+	// TestImports restricts the imports of selected test files.
+	TestImports TestImportsConfig `yaml:"testImports"`
+
+	// TestBudget is the default duration budget of a test, e.g. "30m",
+	// used when neither its README nor its metadata declares one.
+	TestBudget string `yaml:"testBudget"`
+
+	// Visibility restricts which files may import selected packages, on top
+	// of the internal/ package boundaries.
+	Visibility []VisibilityRule `yaml:"visibility"`
+
+	// WantGot configures the expected/actual variable naming rule.
+	WantGot WantGotConfig `yaml:"wantGot"`
+
+	// LoopCapture configures the check for closures capturing loop variables.
+	LoopCapture LoopCaptureConfig `yaml:"loopCapture"`
+
+	// LargeCopy configures the check for large values copied by parameters
+	// and range loops.
+	LargeCopy LargeCopyConfig `yaml:"largeCopy"`
+
+	// GNOISafety configures the check for unguarded disruptive gNOI calls.
+	GNOISafety GNOISafetyConfig `yaml:"gnoiSafety"`
+
+	// Bindings configures the check for stale -binding and testbed paths.
+	Bindings BindingsConfig `yaml:"bindings"`
+
+	// BuildConstraints configures the check for build constraints in tests.
+	BuildConstraints BuildConstraintsConfig `yaml:"buildConstraints"`
+
+	// Sleep configures the time.Sleep ban.
+	Sleep SleepConfig `yaml:"sleep"`
+
+	// Spelling configures the opt-in spell-check of comments, READMEs and
+	// test log messages.
+	Spelling SpellingConfig `yaml:"spelling"`
+
+	// Plugins lists external rule binaries started for every run.
+	Plugins []PluginConfig `yaml:"plugins"`
+
+	// WASMRules lists sandboxed WebAssembly rule modules.
+	WASMRules []WASMRuleConfig `yaml:"wasmRules"`
+
+	// CustomRules defines simple rules without writing Go. They are scoped
+	// and configured under rules: by their name like built-in rules.
+	CustomRules []CustomRuleConfig `yaml:"customRules"`
+
+	// Rules configures individual rules, keyed by rule name.
+	Rules map[string]RuleConfig `yaml:"rules"`
+
+	// Exempt lists files and packages, such as generated bindings, that
+	// some or all rules skip.
+	Exempt []ExemptConfig `yaml:"exempt"`
+
+	// MaxLineSize is the longest line, in bytes, that line-based rules check
+	// in full; longer lines are cut and reported. It defaults to 1 MiB.
+	MaxLineSize int `yaml:"maxLineSize"`
+
+	// MaxFileSize is the largest .go file, in bytes, the rules check; larger
+	// files are skipped with a skipped-file warning. It defaults to 8 MiB.
+	MaxFileSize int64 `yaml:"maxFileSize"`
+
+	// MaxMemory, when set, is the memory budget of a run in bytes. The
+	// garbage collector works to stay under it, and files are skipped with
+	// a skipped-file warning while the heap is over it anyway.
+	MaxMemory int64 `yaml:"maxMemory"`
+
+	// FailOn is the lowest severity that fails a run: "error" (the default),
+	// "warning", "info", or "never" for report-only runs.
+	FailOn string `yaml:"failOn"`
+
+	// Baseline is a file of known findings, written by "validator baseline
+	// create"; runs then only fail on findings it does not list. Relative
+	// paths are resolved against the directory holding the config file.
+	Baseline string `yaml:"baseline"`
+}
+
+// ExemptConfig exempts files from rules.
+type ExemptConfig struct {
+	// Files lists globs of the exempt files; "internal/ocbindings/**"
+	// exempts a whole package tree.
+	Files []string `yaml:"files"`
+
+	// Rules lists the rule names or IDs the files are exempt from; empty
+	// means every rule.
+	Rules []string `yaml:"rules"`
+}
+
+// RuleConfig restricts the files a rule reports on and sets its severity.
+// Globs follow the same syntax as getPrefix.files.
+type RuleConfig struct {
+	// Files, when set, lists the only files the rule applies to,
+	// e.g. "internal/cfgplugins/**".
+	Files []string `yaml:"files"`
+
+	// Ignore lists files the rule never applies to.
+	Ignore []string `yaml:"ignore"`
+
+	// Severity is "error", "warning" or "info"; it defaults to the rule's
+	// own default, error for most rules. Only errors fail validation.
+	Severity string `yaml:"severity"`
+
+	// Tests and NonTests replace Severity in _test.go files and in the other
+	// files; "off" turns the rule off there, e.g. magic-number in test
+	// tables.
+	Tests    string `yaml:"tests"`
+	NonTests string `yaml:"nonTests"`
+
+	// Escalate raises the severity for new code.
+	Escalate *Escalation `yaml:"escalate"`
+}
+
+// Escalation raises a rule's severity for files added to git after a date or
+// matching globs, so new tests are held to rules legacy ones are still
+// being cleaned up for.
+type Escalation struct {
+	// Severity is the escalated severity, "error" by default.
+	Severity string `yaml:"severity"`
+
+	// AddedAfter is a date, e.g. "2025-06-01". Files first committed after
+	// it, or not committed yet, are escalated; outside a git work tree that
+	// is every file.
+	AddedAfter string `yaml:"addedAfter"`
+
+	// Files lists globs of new-code directories that are always escalated,
+	// e.g. "feature/experimental/**".
+	Files []string `yaml:"files"`
+}
+
+// GetPrefixConfig lists functions allowed to keep a Get prefix, typically
+// because they implement an external interface or are generated.
+type GetPrefixConfig struct {
+	// Receivers exempts methods declared on these receiver type names.
+	Receivers []string `yaml:"receivers"`
+
+	// Interfaces exempts methods implementing one of these interfaces,
+	// written as "import/path.Name", e.g. "github.com/openconfig/gnmi/proto/gnmi.GNMIServer".
+	Interfaces []string `yaml:"interfaces"`
+
+	// Files exempts files matching these globs, e.g. "**/*.pb.go".
+	Files []string `yaml:"files"`
+}
+
+// TestImportsConfig limits which packages test files may import, e.g. to
+// keep one feature test suite from importing another.
+type TestImportsConfig struct {
+	// Files selects the _test.go files the rule applies to, e.g. "feature/**".
+	Files []string `yaml:"files"`
+
+	// Deny lists import path globs those files must not import.
+	Deny []string `yaml:"deny"`
+
+	// Allow, when set, lists the only non-standard-library import path
+	// globs those files may import.
+	Allow []string `yaml:"allow"`
+}
+
+// VisibilityRule limits the importers of a set of packages.
+type VisibilityRule struct {
+	// Packages lists import path globs, e.g.
+	// "github.com/openconfig/featureprofiles/internal/cfgplugins".
+	Packages []string `yaml:"packages"`
+
+	// Files lists the only files outside those packages allowed to import
+	// them, e.g. "feature/**/*_test.go".
+	Files []string `yaml:"files"`
+}
+
+// WantGotConfig names the prefixes tests use for expected and actual values.
+// Empty fields fall back to the defaults: want/got, flagging
+// expected/expect/exp and actual/act.
+type WantGotConfig struct {
+	Want     string   `yaml:"want"`
+	Got      string   `yaml:"got"`
+	Expected []string `yaml:"expected"`
+	Actual   []string `yaml:"actual"`
+}
+
+// LoopCaptureConfig configures the loop-capture rule. By default it only
+// reports in modules whose go.mod declares a Go version before 1.22, where
+// every iteration shares one loop variable.
+type LoopCaptureConfig struct {
+	// Always reports regardless of the go.mod version, for code that must
+	// still build with older toolchains.
+	Always bool `yaml:"always"`
+}
+
+// LargeCopyConfig configures the large-copy rule.
+type LargeCopyConfig struct {
+	// Threshold is the size in bytes above which copying a value is
+	// flagged. It defaults to 128.
+	Threshold int64 `yaml:"threshold"`
+}
+
+// GNOISafetyConfig names the code that makes a gNOI reboot, process kill or
+// file removal safe.
+type GNOISafetyConfig struct {
+	// Guards lists identifiers, such as a reservation check or a
+	// -allow_reboot flag, whose use in an enclosing if condition guards the
+	// call.
+	Guards []string `yaml:"guards"`
+
+	// HealthChecks lists functions re-verifying the DUT, e.g.
+	// "sysutil.WaitForReboot", on top of the gnmi Get, Await, Watch and
+	// Lookup calls.
+	HealthChecks []string `yaml:"healthChecks"`
+}
+
+// BindingsConfig configures the binding-ref rule.
+type BindingsConfig struct {
+	// External lists glob patterns of binding and testbed files provided at
+	// run time instead of being checked in, e.g. "/etc/lab/*.binding".
+	External []string `yaml:"external"`
+}
+
+// SleepConfig lists waits banned like time.Sleep.
+type SleepConfig struct {
+	// Equivalents lists wait helpers that sleep a fixed time, e.g.
+	// "vendorutil.WaitSeconds", as written at the call.
+	Equivalents []string `yaml:"equivalents"`
+}
+
+// SpellingConfig adds to the spelling rule's bundled list of misspellings.
+type SpellingConfig struct {
+	// Words lists terms never reported, e.g. a vendor or protocol name that
+	// is spelled like a listed misspelling.
+	Words []string `yaml:"words"`
+
+	// Corrections adds misspellings, mapped to their correction, e.g.
+	// "neighbr: neighbor".
+	Corrections map[string]string `yaml:"corrections"`
+}
+
+// BuildConstraintsConfig configures the build-constraint rule.
+type BuildConstraintsConfig struct {
+	// Allowed lists the approved //go:build expressions, e.g. "linux" or
+	// "integration && !race".
+	Allowed []string `yaml:"allowed"`
+}
+
+// PluginConfig describes one external rule binary.
+type PluginConfig struct {
+	Name string   `yaml:"name"`
+	Path string   `yaml:"path"`
+	Args []string `yaml:"args"`
+
+	// Timeout bounds each check call, e.g. "2s". It defaults to 10s.
+	Timeout string `yaml:"timeout"`
+}
+
+// WASMRuleConfig describes one WebAssembly rule module.
+type WASMRuleConfig struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"`
+
+	// Input selects what the module receives for each file: "source" (the
+	// default) or "ast", which adds the parsed file.
+	Input string `yaml:"input"`
+
+	// Timeout bounds each check call, e.g. "2s". It defaults to 10s.
+	Timeout string `yaml:"timeout"`
+}
+
+// CustomRuleConfig describes a rule defined in the config. It reports every
+// line matching Pattern, every call of Calls and every import of Imports in
+// Go files.
+type CustomRuleConfig struct {
+	// Name is the rule name findings carry, e.g. "no-ioutil".
+	Name string `yaml:"name"`
+
+	// Message replaces the generated message of the findings.
+	Message string `yaml:"message"`
+
+	// Pattern is a regular expression matched against each line.
+	Pattern string `yaml:"pattern"`
+
+	// Calls lists banned package functions, written as
+	// "import/path.Func", e.g. "fmt.Println".
+	Calls []string `yaml:"calls"`
+
+	// Imports lists globs of banned import paths, e.g. "io/ioutil".
+	Imports []string `yaml:"imports"`
+}
+
+// LoadConfig reads and decodes the YAML config file at path.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+
+	cfg := &Config{}
+	if err := decodeConfig(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	for i, root := range cfg.Roots {
+		if !filepath.IsAbs(root) {
+			cfg.Roots[i] = filepath.Join(dir, root)
+		}
+	}
+	if cfg.Baseline != "" && !filepath.IsAbs(cfg.Baseline) {
+		cfg.Baseline = filepath.Join(dir, cfg.Baseline)
+	}
+	if cfg.TestBudget != "" {
+		if _, err := time.ParseDuration(cfg.TestBudget); err != nil {
+			return nil, fmt.Errorf("parsing config %s: testBudget: %w", path, err)
+		}
+	}
+	for i, p := range cfg.Plugins {
+		if p.Name == "" {
+			return nil, fmt.Errorf("parsing config %s: plugin %d has no name", path, i+1)
+		}
+		if p.Path == "" {
+			return nil, fmt.Errorf("parsing config %s: plugin %q has no path", path, p.Name)
+		}
+		if !filepath.IsAbs(p.Path) && strings.ContainsRune(p.Path, filepath.Separator) {
+			cfg.Plugins[i].Path = filepath.Join(dir, p.Path)
+		}
+	}
+	for i, w := range cfg.WASMRules {
+		if w.Name == "" || w.Path == "" {
+			return nil, fmt.Errorf("parsing config %s: wasm rule %d needs a name and a path", path, i+1)
+		}
+		if !filepath.IsAbs(w.Path) {
+			cfg.WASMRules[i].Path = filepath.Join(dir, w.Path)
+		}
+	}
+
+	if cfg.Profile != "" {
+		if cfg, err = ApplyProfile(cfg, cfg.Profile); err != nil {
+			return nil, fmt.Errorf("parsing config %s: %w", path, err)
+		}
+	}
+	if _, err := ParseFailOn(cfg.FailOn); err != nil {
+		return nil, fmt.Errorf("parsing config %s: failOn: %w", path, err)
+	}
+
+	if _, err := compileCustomRules(cfg.CustomRules); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+
+	for i, e := range cfg.Exempt {
+		if len(e.Files) == 0 {
+			return nil, fmt.Errorf("parsing config %s: exempt entry %d needs files", path, i+1)
+		}
+	}
+
+	for name, rule := range cfg.Rules {
+		if _, err := parseSeverity(rule.Severity); err != nil {
+			return nil, fmt.Errorf("parsing config %s: rules.%s: %w", path, name, err)
+		}
+		for key, sev := range map[string]string{"tests": rule.Tests, "nonTests": rule.NonTests} {
+			if sev == "off" {
+				continue
+			}
+			if _, err := parseSeverity(sev); err != nil {
+				return nil, fmt.Errorf("parsing config %s: rules.%s.%s: %w", path, name, key, err)
+			}
+		}
+		if esc := rule.Escalate; esc != nil {
+			if _, err := parseSeverity(esc.Severity); err != nil {
+				return nil, fmt.Errorf("parsing config %s: rules.%s.escalate: %w", path, name, err)
+			}
+			if esc.AddedAfter != "" {
+				if _, err := time.Parse(time.DateOnly, esc.AddedAfter); err != nil {
+					return nil, fmt.Errorf("parsing config %s: rules.%s.escalate.addedAfter: %w", path, name, err)
+				}
+			}
+		}
+	}
+
+	return cfg, nil
+}
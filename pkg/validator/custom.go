@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// customRule is a CustomRuleConfig ready to run.
+type customRule struct {
+	CustomRuleConfig
+	pattern *regexp.Regexp
+}
+
+// compileCustomRules checks the custom rules of the config and compiles
+// their patterns.
+func compileCustomRules(cfgs []CustomRuleConfig) ([]customRule, error) {
+	var rules []customRule
+	for i, c := range cfgs {
+		switch {
+		case c.Name == "":
+			return nil, fmt.Errorf("custom rule %d has no name", i+1)
+		case RuleID(c.Name) != "" || registered[c.Name] != nil:
+			return nil, fmt.Errorf("custom rule %s: name is taken by a built-in rule", c.Name)
+		case c.Pattern == "" && len(c.Calls) == 0 && len(c.Imports) == 0:
+			return nil, fmt.Errorf("custom rule %s needs a pattern, calls or imports", c.Name)
+		}
+		rule := customRule{CustomRuleConfig: c}
+		if c.Pattern != "" {
+			re, err := regexp.Compile(c.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("custom rule %s: %w", c.Name, err)
+			}
+			rule.pattern = re
+		}
+		for _, call := range c.Calls {
+			if i := strings.LastIndex(call, "."); i <= 0 || i == len(call)-1 {
+				return nil, fmt.Errorf("custom rule %s: call %q is not written as import/path.Func", c.Name, call)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// validateCustomRules runs the custom rules of the config against file.
+func (v *Validator) validateCustomRules(file *File, errs *[]Issue) {
+	for _, rule := range v.customRules {
+		if rule.pattern != nil {
+			v.forEachLine(file.Path, errs, func(lineNo int, line string) {
+				if rule.pattern.MatchString(line) {
+					rule.report(errs, token.Position{Filename: file.Path, Line: lineNo}, "line matches %s", rule.Pattern)
+				}
+			})
+		}
+
+		for _, imp := range file.AST.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			if matchAnyGlob(rule.Imports, path) {
+				rule.report(errs, file.Fset.Position(imp.Pos()), "import of %q is banned", path)
+			}
+		}
+
+		if len(rule.Calls) == 0 {
+			continue
+		}
+		// Resolve the local package name of each banned call in this file.
+		local := make(map[string]string)
+		for _, call := range rule.Calls {
+			i := strings.LastIndex(call, ".")
+			if name := localImportName(file.AST, call[:i]); name != "" && name != "_" {
+				local[call] = name
+			}
+		}
+		ast.Inspect(file.AST, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			for banned, pkg := range local {
+				if isPkgCall(call, pkg, banned[strings.LastIndex(banned, ".")+1:]) {
+					rule.report(errs, file.Fset.Position(call.Pos()), "call of %s is banned", banned)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// report adds a finding of r, using the configured message when there is
+// one and the generated one otherwise.
+func (r customRule) report(errs *[]Issue, pos token.Position, format string, args ...any) {
+	if r.Message != "" {
+		report(errs, r.Name, pos, "%s", r.Message)
+		return
+	}
+	report(errs, r.Name, pos, format, args...)
+}
@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"fmt"
+	"time"
+)
+
+// Deprecation describes how a rule in StageDeprecated is phased out.
+type Deprecation struct {
+	// RemovedAfter is the date, e.g. "2027-01-31", after which the rule no
+	// longer runs. Empty means no date is set yet.
+	RemovedAfter string
+
+	// Replacement names the rule taking over, if any.
+	Replacement string
+}
+
+// Deprecated is implemented by rules in StageDeprecated that announce their
+// removal date or replacement.
+type Deprecated interface {
+	Deprecation() Deprecation
+}
+
+// deprecations holds the registered rules in StageDeprecated by rule ID.
+// Their findings are info notices, so they no longer fail runs.
+var deprecations = make(map[string]Deprecation)
+
+// registerDeprecation records r when it is deprecated.
+func registerDeprecation(r Rule) {
+	if RuleStage(r) != StageDeprecated {
+		return
+	}
+	var d Deprecation
+	if dr, ok := r.(Deprecated); ok {
+		d = dr.Deprecation()
+	}
+	if d.RemovedAfter != "" {
+		if _, err := time.Parse(time.DateOnly, d.RemovedAfter); err != nil {
+			panic(fmt.Sprintf("validator: rule %q: bad removal date: %v", r.ID(), err))
+		}
+	}
+	deprecations[r.ID()] = d
+	defaultSeverities[r.ID()] = SeverityInfo
+}
+
+// retired reports whether the removal date of the deprecated rule id has
+// passed, so the rule no longer runs.
+func retired(id string) bool {
+	d, ok := deprecations[id]
+	if !ok || d.RemovedAfter == "" {
+		return false
+	}
+	after, _ := time.Parse(time.DateOnly, d.RemovedAfter)
+	return time.Now().After(after.AddDate(0, 0, 1))
+}
+
+// Notices returns one line for every deprecated rule v would run or has
+// retired, so pipelines learn about removals before they happen.
+func (v *Validator) Notices() []string {
+	var notices []string
+	for _, r := range registry {
+		d, ok := deprecations[r.ID()]
+		if !ok || v.disabled[r.ID()] {
+			continue
+		}
+		var notice string
+		if retired(r.ID()) {
+			notice = fmt.Sprintf("rule %s was removed after %s and no longer runs", r.ID(), d.RemovedAfter)
+		} else {
+			notice = fmt.Sprintf("rule %s is deprecated and only reports notices", r.ID())
+			if d.RemovedAfter != "" {
+				notice += fmt.Sprintf("; it will be removed after %s", d.RemovedAfter)
+			}
+		}
+		if d.Replacement != "" {
+			notice += fmt.Sprintf("; use %s instead", d.Replacement)
+		}
+		notices = append(notices, notice)
+	}
+	return notices
+}
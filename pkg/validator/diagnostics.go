@@ -0,0 +1,357 @@
+package validator
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Issue is a single finding reported by a rule.
+type Issue struct {
+	// Rule identifies the rule that produced the finding, e.g. "get-prefix".
+	Rule string
+	// ID is the stable ID of Rule, e.g. "FP006", or "" when it has none.
+	ID string
+	// Pos locates the finding. Line and Column are zero when unknown.
+	Pos      token.Position
+	Severity Severity
+	// Message describes the problem.
+	Message string
+	// Suggestion says how to resolve it, e.g. "use fmt.Errorf instead", or is
+	// "" when the rule gives no advice.
+	Suggestion string
+	// Also lists the other rules that reported the same problem at Pos.
+	Also []string
+	// Fix holds the edits resolving the finding, when a rule offers one.
+	Fix []TextEdit
+}
+
+// String formats d as "file:line: message".
+func (d Issue) String() string {
+	var b strings.Builder
+	b.WriteString(d.Pos.Filename)
+	if d.Pos.Line > 0 {
+		fmt.Fprintf(&b, ":%d", d.Pos.Line)
+	}
+	b.WriteString(": ")
+	switch d.Severity {
+	case SeverityWarning:
+		b.WriteString("warning: ")
+	case SeverityInfo:
+		b.WriteString("info: ")
+	}
+	if d.ID != "" {
+		fmt.Fprintf(&b, "[%s] ", d.ID)
+	}
+	b.WriteString(d.Text())
+	if len(d.Also) > 0 {
+		fmt.Fprintf(&b, " (also reported by %s)", strings.Join(d.Also, ", "))
+	}
+	return b.String()
+}
+
+// Text joins the message and suggestion of d as rules write them:
+// "problem; suggestion".
+func (d Issue) Text() string {
+	if d.Suggestion == "" {
+		return d.Message
+	}
+	return d.Message + "; " + d.Suggestion
+}
+
+// report appends a finding of rule at pos to diags, with the message
+// formatted from format and args.
+func report(diags *[]Issue, rule string, pos token.Position, format string, args ...any) {
+	reportSuggestion(diags, rule, pos, fmt.Sprintf(format, args...), "")
+}
+
+// suggest is report for findings that say how to resolve them: suggestion
+// becomes the Suggestion of the finding rather than part of its message.
+func suggest(diags *[]Issue, rule string, pos token.Position, suggestion, format string, args ...any) {
+	reportSuggestion(diags, rule, pos, fmt.Sprintf(format, args...), suggestion)
+}
+
+// reportSuggestion appends a finding of rule at pos with an explicit
+// suggestion, e.g. one returned by a plugin.
+func reportSuggestion(diags *[]Issue, rule string, pos token.Position, message, suggestion string) {
+	*diags = append(*diags, Issue{
+		Rule:       rule,
+		ID:         RuleID(rule),
+		Pos:        pos,
+		Severity:   SeverityError,
+		Message:    message,
+		Suggestion: suggestion,
+	})
+}
+
+// SortIssues sorts diags by file, line, column and rule ID, so reports do
+// not depend on the order files were walked or checked in. Findings that
+// tie keep their order.
+func SortIssues(diags []Issue) {
+	slices.SortStableFunc(diags, func(a, b Issue) int {
+		return cmp.Or(
+			strings.Compare(a.Pos.Filename, b.Pos.Filename),
+			cmp.Compare(a.Pos.Line, b.Pos.Line),
+			cmp.Compare(a.Pos.Column, b.Pos.Column),
+			strings.Compare(a.ID, b.ID),
+			strings.Compare(a.Rule, b.Rule),
+		)
+	})
+}
+
+// joinIssues formats diags one per line.
+func joinIssues(diags []Issue) string {
+	lines := make([]string, len(diags))
+	for i, d := range diags {
+		lines[i] = d.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// equivalentRules groups rules that give the same guidance. When several
+// rules of a group fire at the same position only the first is kept.
+var equivalentRules = [][]string{
+	{"underscore", "mixed-caps", "var-mixed-caps", "const-name"},
+	{"doc-comment", "comment-name", "deviation-comment"},
+	{"initialism", "acronym"},
+}
+
+// ruleGroup maps each rule to the name of its equivalence group.
+var ruleGroup = func() map[string]string {
+	groups := make(map[string]string)
+	for _, group := range equivalentRules {
+		for _, rule := range group {
+			groups[rule] = group[0]
+		}
+	}
+	return groups
+}()
+
+// dedupeIssues drops exact duplicates and folds findings of equivalent
+// rules at the same position into the first one, recording the folded rules
+// in its Also field. The order of the remaining findings is preserved.
+func dedupeIssues(diags []Issue) []Issue {
+	type key struct {
+		file      string
+		line, col int
+		group     string
+	}
+
+	var out []Issue
+	exact := make(map[string]bool)
+	kept := make(map[key]int)
+
+	for _, d := range diags {
+		if id := d.Rule + "\x00" + d.String(); exact[id] {
+			continue
+		} else {
+			exact[id] = true
+		}
+
+		group, ok := ruleGroup[d.Rule]
+		if !ok {
+			out = append(out, d)
+			continue
+		}
+
+		k := key{d.Pos.Filename, d.Pos.Line, d.Pos.Column, group}
+		i, seen := kept[k]
+		if !seen {
+			kept[k] = len(out)
+			out = append(out, d)
+			continue
+		}
+
+		// Several findings of one rule at a position, e.g. two identifiers
+		// in one declaration, are distinct problems.
+		if out[i].Rule == d.Rule {
+			out = append(out, d)
+			continue
+		}
+		out[i].Severity = max(out[i].Severity, d.Severity)
+		if !containsString(out[i].Also, d.Rule) {
+			out[i].Also = append(out[i].Also, d.Rule)
+		}
+	}
+
+	return out
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Severity says whether an Issue fails validation. Only errors do.
+type Severity int
+
+const (
+	// SeverityInfo marks suggestions, e.g. performance hints.
+	SeverityInfo Severity = iota + 1
+	// SeverityWarning marks problems to fix that do not block a change.
+	SeverityWarning
+	// SeverityError marks findings failing validation.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// MarshalText writes s by name, e.g. "warning", as JSON reports do.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText reads a severity written by MarshalText.
+func (s *Severity) UnmarshalText(text []byte) error {
+	sev, err := parseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = sev
+	return nil
+}
+
+// parseSeverity parses a configured severity; "" means error.
+func parseSeverity(s string) (Severity, error) {
+	switch s {
+	case "", "error":
+		return SeverityError, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "info":
+		return SeverityInfo, nil
+	}
+	return 0, fmt.Errorf("unknown severity %q, want error, warning or info", s)
+}
+
+// ParseFailOn parses an exit-code policy: the lowest severity that fails a
+// run, "error" by default, or "never" for report-only runs, returned as 0.
+func ParseFailOn(s string) (Severity, error) {
+	if s == "never" {
+		return 0, nil
+	}
+	sev, err := parseSeverity(s)
+	if err != nil {
+		return 0, fmt.Errorf("unknown fail-on policy %q, want error, warning, info or never", s)
+	}
+	return sev, nil
+}
+
+// defaultSeverities holds the severity of the rules whose findings are not
+// errors unless the config says otherwise, keyed by rule name.
+var defaultSeverities = make(map[string]Severity)
+
+// defaultSeverity returns the severity of rule's findings when the config
+// does not set one.
+func defaultSeverity(rule string) Severity {
+	if sev, ok := defaultSeverities[rule]; ok {
+		return sev
+	}
+	return SeverityError
+}
+
+// DefaultSeverity returns the severity of the named rule's findings when the
+// config does not set one, e.g. for the rule metadata of a SARIF report.
+func DefaultSeverity(rule string) Severity {
+	return defaultSeverity(canonicalRule(rule))
+}
+
+// inScope reports whether rule applies to path under the rule configs and
+// exemptions.
+func (v *Validator) inScope(rule, path string) bool {
+	for _, e := range v.cfg.Exempt {
+		if len(e.Rules) > 0 && !slices.ContainsFunc(e.Rules, func(r string) bool { return canonicalRule(r) == rule }) {
+			continue
+		}
+		if matchAnyGlob(e.Files, path) {
+			return false
+		}
+	}
+
+	cfg, ok := v.rules[rule]
+	if !ok {
+		return true
+	}
+	if cfg.fileSeverity(path) == "off" {
+		return false
+	}
+	if len(cfg.Files) > 0 && !matchAnyGlob(cfg.Files, path) {
+		return false
+	}
+	return !matchAnyGlob(cfg.Ignore, path)
+}
+
+// fileSeverity returns the configured severity of the rule in path: the
+// tests or nonTests setting matching the file, else Severity.
+func (cfg RuleConfig) fileSeverity(path string) string {
+	scoped := cfg.NonTests
+	if isTestFile(path) {
+		scoped = cfg.Tests
+	}
+	if scoped != "" {
+		return scoped
+	}
+	return cfg.Severity
+}
+
+// ruleSeverity returns the severity of rule's findings in path.
+func (v *Validator) ruleSeverity(rule, path string) Severity {
+	cfg, ok := v.rules[rule]
+	if !ok {
+		return defaultSeverity(rule)
+	}
+	sev := defaultSeverity(rule)
+	if s := cfg.fileSeverity(path); s != "" {
+		sev, _ = parseSeverity(s)
+	}
+
+	esc := cfg.Escalate
+	if esc == nil {
+		return sev
+	}
+	escalate := matchAnyGlob(esc.Files, path)
+	if !escalate && esc.AddedAfter != "" {
+		after, _ := time.Parse(time.DateOnly, esc.AddedAfter)
+		escalate = fileAddedDate(path).After(after)
+	}
+	if escalate {
+		escSev, _ := parseSeverity(esc.Severity)
+		sev = max(sev, escSev)
+	}
+	return sev
+}
+
+// applyRuleConfigs drops the findings of disabled rules and of rules scoped
+// away from their file, and sets the severity of the others.
+func (v *Validator) applyRuleConfigs(diags []Issue) []Issue {
+	out := diags[:0]
+	for _, d := range diags {
+		if !v.disabled[d.Rule] && v.tagSelected(d.Rule) && v.inScope(d.Rule, d.Pos.Filename) {
+			if d.ID == "" {
+				// Issues built by registered rules rather than report.
+				d.ID = RuleID(d.Rule)
+			}
+			d.Severity = v.ruleSeverity(d.Rule, d.Pos.Filename)
+			out = append(out, d)
+		}
+	}
+	return out
+}
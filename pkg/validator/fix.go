@@ -0,0 +1,53 @@
+package validator
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+)
+
+// TextEdit replaces the source bytes in [Start, End) with NewText. Start and
+// End are byte offsets into the file.
+type TextEdit struct {
+	Start   int
+	End     int
+	NewText string
+}
+
+// applyEdits rewrites the file at path with edits applied and the result
+// gofmt-ed. Edits must not overlap.
+func applyEdits(path string, edits []TextEdit) error {
+	if len(edits) == 0 {
+		return nil
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	sorted := append([]TextEdit(nil), edits...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start > sorted[j].Start
+	})
+
+	out := src
+	for _, e := range sorted {
+		if e.Start < 0 || e.End > len(out) || e.Start > e.End {
+			return fmt.Errorf("%s: edit [%d, %d) is out of range", path, e.Start, e.End)
+		}
+		out = append(out[:e.Start:e.Start], append([]byte(e.NewText), out[e.End:]...)...)
+	}
+
+	formatted, err := format.Source(out)
+	if err != nil {
+		return fmt.Errorf("%s: fixed source does not parse: %w", path, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, formatted, info.Mode())
+}
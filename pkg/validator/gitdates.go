@@ -0,0 +1,76 @@
+package validator
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// gitTopLevels caches the work tree root of each directory, "" outside git.
+var gitTopLevels = make(map[string]string)
+
+// gitAddedDates caches, per work tree root, when each tracked file was
+// first committed.
+var gitAddedDates = make(map[string]map[string]time.Time)
+
+// fileAddedDate returns when path was first committed to git. Files not
+// committed yet count as added now, and so do all files when path is not in
+// a git work tree or git is unavailable: without history, nothing is known
+// to predate a date.
+func fileAddedDate(path string) time.Time {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return time.Now()
+	}
+
+	dir := filepath.Dir(abs)
+	top, ok := gitTopLevels[dir]
+	if !ok {
+		out, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+		if err == nil {
+			top = strings.TrimSpace(string(out))
+		}
+		gitTopLevels[dir] = top
+	}
+	if top == "" {
+		return time.Now()
+	}
+
+	dates, ok := gitAddedDates[top]
+	if !ok {
+		dates = loadAddedDates(top)
+		gitAddedDates[top] = dates
+	}
+	if added, ok := dates[abs]; ok {
+		return added
+	}
+	return time.Now()
+}
+
+// loadAddedDates lists the commit date of the commit adding each file in
+// the work tree at top.
+func loadAddedDates(top string) map[string]time.Time {
+	dates := make(map[string]time.Time)
+	out, err := exec.Command("git", "-C", top, "log", "--diff-filter=A", "--format=%x00%cI", "--name-only").Output()
+	if err != nil {
+		return dates
+	}
+
+	// The log runs newest first, so a file re-added after a deletion ends
+	// up with the date it was first added.
+	for _, entry := range bytes.Split(out, []byte{0})[1:] {
+		lines := strings.Split(strings.TrimSpace(string(entry)), "\n")
+		committed, err := time.Parse(time.RFC3339, lines[0])
+		if err != nil {
+			continue
+		}
+		for _, name := range lines[1:] {
+			if name = strings.TrimSpace(name); name != "" {
+				dates[filepath.Join(top, filepath.FromSlash(name))] = committed
+			}
+		}
+	}
+	return dates
+}
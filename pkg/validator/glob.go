@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// globCache holds compiled glob patterns keyed by their source.
+var globCache = make(map[string]*regexp.Regexp)
+
+// matchGlob reports whether path matches the glob pattern. Besides the usual
+// '*', '?' and character classes, '**' matches any number of directories.
+// Patterns are not anchored to a root: "internal/generated/**" matches that
+// directory wherever it appears in path.
+func matchGlob(pattern, path string) bool {
+	re, ok := globCache[pattern]
+	if !ok {
+		re = regexp.MustCompile(globRegexp(pattern))
+		globCache[pattern] = re
+	}
+	return re.MatchString(filepath.ToSlash(path))
+}
+
+// globRegexp returns the regexp matchGlob compiles pattern into.
+func globRegexp(pattern string) string {
+	return `(^|/)` + globToRegexp(filepath.ToSlash(pattern)) + `$`
+}
+
+// checkGlob reports whether pattern is a valid glob, e.g. not "[z-a]".
+func checkGlob(pattern string) error {
+	if _, err := regexp.Compile(globRegexp(pattern)); err != nil {
+		return fmt.Errorf("bad glob %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// matchAnyGlob reports whether path matches at least one of patterns.
+func matchAnyGlob(patterns []string, path string) bool {
+	for _, p := range patterns {
+		if matchGlob(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp translates a glob pattern into an unanchored regexp.
+func globToRegexp(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString(`(.*/)?`)
+			i += 2
+		case c == '*' && strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(`.*`)
+			i++
+		case c == '*':
+			b.WriteString(`[^/]*`)
+		case c == '?':
+			b.WriteString(`[^/]`)
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
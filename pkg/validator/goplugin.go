@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+	"sort"
+)
+
+// goPluginSymbol is the function a Go plugin exports to hand over its rules.
+const goPluginSymbol = "Rules"
+
+// LoadGoPlugins opens every .so file in dir and registers the rules they
+// export. A plugin is a main package built with -buildmode=plugin against the
+// same validator version and Go toolchain as the binary, exporting
+//
+//	func Rules() []validator.Rule
+//
+// Plugins cannot be unloaded, so call it once, before creating Validators.
+func LoadGoPlugins(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		if _, err := os.Stat(dir); err != nil {
+			return fmt.Errorf("plugin dir: %w", err)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		p, err := plugin.Open(path)
+		if err != nil {
+			return fmt.Errorf("loading plugin %s: %w", path, err)
+		}
+		sym, err := p.Lookup(goPluginSymbol)
+		if err != nil {
+			return fmt.Errorf("plugin %s: %w", path, err)
+		}
+		rules, ok := sym.(func() []Rule)
+		if !ok {
+			return fmt.Errorf("plugin %s: %s is %T, want func() []validator.Rule", path, goPluginSymbol, sym)
+		}
+		for _, r := range rules() {
+			if _, ok := registered[r.ID()]; ok {
+				return fmt.Errorf("plugin %s: rule %q is already registered", path, r.ID())
+			}
+			Register(r)
+		}
+	}
+	return nil
+}
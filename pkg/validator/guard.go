@@ -0,0 +1,76 @@
+package validator
+
+import (
+	"go/token"
+	"os"
+	"runtime/debug"
+	"runtime/metrics"
+)
+
+// defaultMaxFileSize is the largest .go file, in bytes, the rules look at
+// unless configured otherwise. Hand-written Go is far smaller; larger files
+// are generated data that only risk exhausting memory.
+const defaultMaxFileSize = 8 << 20
+
+// Skipping a file leaves it unchecked but is not a problem with the code.
+func init() { defaultSeverities["skipped-file"] = SeverityWarning }
+
+// maxFileSize returns the file size limit of the options or config.
+func (v *Validator) maxFileSize() int64 {
+	switch {
+	case v.opts.MaxFileSize > 0:
+		return v.opts.MaxFileSize
+	case v.cfg.MaxFileSize > 0:
+		return v.cfg.MaxFileSize
+	}
+	return defaultMaxFileSize
+}
+
+// maxMemory returns the memory budget of the options or config, 0 for none.
+func (v *Validator) maxMemory() int64 {
+	if v.opts.MaxMemory > 0 {
+		return v.opts.MaxMemory
+	}
+	return v.cfg.MaxMemory
+}
+
+// setMemoryLimit makes the garbage collector keep the heap within the
+// memory budget, when there is one, before files have to be skipped.
+func (v *Validator) setMemoryLimit() {
+	if limit := v.maxMemory(); limit > 0 {
+		debug.SetMemoryLimit(limit)
+	}
+}
+
+// skipFile reports whether path is left out of the rules to keep the run
+// from running out of memory: files over the size limit, and every file
+// while the heap stays over the memory budget even after a collection.
+// Skipped files are reported as skipped-file warnings instead of the job
+// being killed.
+func (v *Validator) skipFile(path string, errs *[]Issue) bool {
+	info, err := os.Stat(path)
+	if err == nil && info.Size() > v.maxFileSize() {
+		suggest(errs, "skipped-file", token.Position{Filename: path}, "raise maxFileSize if it is hand-written", "file is %d bytes, over the %d byte limit, and was not checked", info.Size(), v.maxFileSize())
+		return true
+	}
+	limit := v.maxMemory()
+	if limit <= 0 || heapBytes() <= limit {
+		return false
+	}
+	debug.FreeOSMemory()
+	if heapBytes() <= limit {
+		return false
+	}
+	suggest(errs, "skipped-file", token.Position{Filename: path}, "raise maxMemory or validate fewer roots at once", "memory use is over the %d byte budget, so the file was not checked", limit)
+	return true
+}
+
+// heapBytes returns the bytes taken by live and not yet swept heap objects.
+func heapBytes() int64 {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return int64(sample[0].Value.Uint64())
+}
@@ -0,0 +1,6138 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"go/version"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// validateDocComments requires exported functions other than tests to carry
+// a doc comment starting with their name and ending with a period.
+func validateDocComments(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || !fn.Name.IsExported() || strings.HasPrefix(fn.Name.Name, "Test") {
+			continue
+		}
+		pos := fs.Position(fn.Pos())
+
+		if fn.Doc == nil {
+			report(errs, "doc-comment", pos, "exported function %q must have doc comment", fn.Name.Name)
+			continue
+		}
+		text := strings.TrimSpace(fn.Doc.Text())
+
+		// Check if comment ends with a period
+		if !strings.HasSuffix(text, ".") {
+			report(errs, "doc-comment", pos, "function comment should end with '.'")
+		}
+
+		// Check if comment starts with exact function name (case-sensitive)
+		if !strings.HasPrefix(text, fn.Name.Name) {
+			report(errs, "doc-comment", pos, "doc comment for function %q should start with the function name(check for case sensitive)", fn.Name.Name)
+		}
+	}
+}
+
+// validateHelperAssertions flags helpers calling t.Error or t.Errorf
+// instead of returning an error to the test.
+func validateHelperAssertions(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || strings.HasPrefix(fn.Name.Name, "Test") {
+			continue
+		}
+		pos := fs.Position(fn.Pos())
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if callExpr, ok := n.(*ast.CallExpr); ok {
+				if sel, ok := callExpr.Fun.(*ast.SelectorExpr); ok {
+					if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == "t" {
+						switch sel.Sel.Name {
+						case "Error", "Errorf":
+							suggest(errs, "helper-assertion", pos, "return error instead", "helper function %q should not call t.%s directly", fn.Name.Name, sel.Sel.Name)
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// validateGetPrefix bans the Get prefix on functions outside the configured
+// exemptions.
+func (v *Validator) validateGetPrefix(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if ok && strings.HasPrefix(fn.Name.Name, "Get") && !v.getPrefixExempt(path, fs, f, fn, errs) {
+			pos := fs.Position(fn.Pos())
+			report(errs, "get-prefix", pos, "function %s should not use Get prefix", fn.Name.Name)
+		}
+	}
+}
+
+// validateTestHelpers requires test helpers taking a *testing.T to call
+// t.Helper().
+func validateTestHelpers(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || strings.HasPrefix(fn.Name.Name, "Test") {
+			continue
+		}
+		pos := fs.Position(fn.Pos())
+
+		var tName string
+		if fn.Type.Params != nil {
+			for _, param := range fn.Type.Params.List {
+				if starExpr, ok := param.Type.(*ast.StarExpr); ok {
+					if selExpr, ok := starExpr.X.(*ast.SelectorExpr); ok {
+						if pkgIdent, ok := selExpr.X.(*ast.Ident); ok &&
+							pkgIdent.Name == "testing" &&
+							selExpr.Sel.Name == "T" {
+							if len(param.Names) > 0 {
+								tName = param.Names[0].Name
+								break
+							}
+						}
+					}
+				}
+			}
+		}
+
+		// Step 2: If tName is found, check for t.Helper() call
+		if tName != "" {
+			foundHelper := false
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				if ce, ok := n.(*ast.CallExpr); ok {
+					if sel, ok := ce.Fun.(*ast.SelectorExpr); ok {
+						if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == tName && sel.Sel.Name == "Helper" {
+							foundHelper = true
+						}
+					}
+				}
+				return true
+			})
+
+			if !foundHelper {
+				report(errs, "test-helper", pos, "test helper function %s should call %s.Helper()", fn.Name.Name, tName)
+			}
+		}
+	}
+}
+
+// validateTestHelperNames requires test file helpers to be unexported.
+func validateTestHelperNames(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || strings.HasPrefix(fn.Name.Name, "Test") {
+			continue
+		}
+		if len(fn.Name.Name) > 0 {
+			firstChar := fn.Name.Name[0:1]
+			if strings.ToUpper(firstChar) == firstChar {
+				pos := fs.Position(fn.Pos())
+				report(errs, "test-helper-name", pos, "test function %s must start with lowercase letter", fn.Name.Name)
+			}
+		}
+	}
+}
+
+// validateStructParams runs checkStructParameterUsage on every function.
+func validateStructParams(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok {
+			*errs = append(*errs, checkStructParameterUsage(path, fn, fs)...)
+		}
+	}
+}
+
+// validateUnderscores bans underscores in identifiers: file scope objects,
+// struct fields, interface methods, methods and receivers. Receivers must
+// also use mixedCaps.
+func validateUnderscores(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	for _, obj := range f.Scope.Objects {
+		if strings.Contains(obj.Name, "_") {
+			pos := fs.Position(obj.Pos())
+			report(errs, "underscore", pos, "identifier %s should not contain underscores", obj.Name)
+		}
+	}
+
+	// Struct fields and interface methods are not part of the file scope.
+	ast.Inspect(f, func(n ast.Node) bool {
+		ts, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		var members *ast.FieldList
+		kind := "field"
+		switch t := ts.Type.(type) {
+		case *ast.StructType:
+			members = t.Fields
+		case *ast.InterfaceType:
+			members = t.Methods
+			kind = "interface method"
+		default:
+			return true
+		}
+		for _, member := range members.List {
+			for _, name := range member.Names {
+				if name.Name != "_" && strings.Contains(name.Name, "_") {
+					pos := fs.Position(name.Pos())
+					report(errs, "underscore", pos, "%s %s.%s should not contain underscores", kind, ts.Name.Name, name.Name)
+				}
+			}
+		}
+		return true
+	})
+
+	// Methods and receivers are not part of the file scope.
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil {
+			continue
+		}
+		if strings.Contains(fn.Name.Name, "_") {
+			pos := fs.Position(fn.Name.Pos())
+			report(errs, "underscore", pos, "method %s should not contain underscores", fn.Name.Name)
+		}
+		for _, field := range fn.Recv.List {
+			for _, name := range field.Names {
+				if name.Name == "_" {
+					continue
+				}
+				pos := fs.Position(name.Pos())
+				if strings.Contains(name.Name, "_") {
+					report(errs, "underscore", pos, "receiver %s of method %s should not contain underscores", name.Name, fn.Name.Name)
+				} else if !unexportedMixedCaps.MatchString(name.Name) {
+					report(errs, "receiver-name", pos, "receiver %s of method %s should use mixedCaps", name.Name, fn.Name.Name)
+				}
+			}
+		}
+	}
+}
+
+// validateVarTypeNames flags package variables repeating their type in
+// their name.
+func validateVarTypeNames(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	for _, decl := range f.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.VAR {
+			for _, spec := range gd.Specs {
+				if vs, ok := spec.(*ast.ValueSpec); ok {
+					if vs.Type != nil {
+						typeName := fmt.Sprintf("%s", vs.Type)
+						for _, name := range vs.Names {
+							if strings.Contains(strings.ToLower(name.Name), strings.ToLower(typeName)) {
+								pos := fs.Position(name.Pos())
+								report(errs, "var-type-name", pos, "variable %s repeats its type %s in name", name.Name, typeName)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func validateNestedAnonymousFuncs(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		callExpr, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		for _, arg := range callExpr.Args {
+			if funcLit, ok := arg.(*ast.FuncLit); ok {
+				pos := fs.Position(funcLit.Pos())
+				suggest(errs, "nested-func-literal", pos, "defining the watch function seperately to improve the readability.", "avoid nesting anonymous function inside call")
+			}
+		}
+
+		return true
+	})
+}
+
+func validateMustUsage(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		funcName := fn.Name.Name
+		if strings.HasPrefix(funcName, "must") || strings.HasPrefix(funcName, "Must") {
+			// Function already starts with mustXYZ, skip validation
+			continue
+		}
+
+		pos := fs.Position(fn.Pos())
+		usesMust := false
+		usesFatalErr := false
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			// Detect mustXYZ usage
+			if callExpr, ok := n.(*ast.CallExpr); ok {
+				if ident, ok := callExpr.Fun.(*ast.Ident); ok {
+					if strings.HasPrefix(ident.Name, "must") || strings.HasPrefix(ident.Name, "Must") {
+						usesMust = true
+					}
+				}
+			}
+
+			// Detect if err != nil followed by t.Fatalf
+			if ifStmt, ok := n.(*ast.IfStmt); ok {
+				if cond, ok := ifStmt.Cond.(*ast.BinaryExpr); ok {
+					if ident, ok := cond.X.(*ast.Ident); ok && ident.Name == "err" {
+						if cond.Op == token.NEQ {
+							// Check if body contains t.Fatalf
+							ast.Inspect(ifStmt.Body, func(n ast.Node) bool {
+								if callExpr, ok := n.(*ast.CallExpr); ok {
+									if sel, ok := callExpr.Fun.(*ast.SelectorExpr); ok {
+										if xIdent, ok := sel.X.(*ast.Ident); ok && xIdent.Name == "t" && sel.Sel.Name == "Fatalf" {
+											usesFatalErr = true
+										}
+									}
+								}
+								return true
+							})
+						}
+					}
+				}
+			}
+
+			return true
+		})
+
+		if usesFatalErr && !usesMust {
+			report(errs, "must-prefix", pos, "function %s should start with mustXYZ", funcName)
+		}
+	}
+}
+
+// defaultAcronyms lists the acronyms identifiers must spell as given. The
+// config adds repository specific ones, e.g. protocol names.
+var defaultAcronyms = []string{"DUT", "IP", "MAC", "ATE", "IPv4", "IPv6", "OTG"}
+
+func (v *Validator) validateAcronyms(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	acronymMap := make(map[string]string)
+	for _, a := range v.acronyms {
+		acronymMap[strings.ToLower(a)] = a
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			name := ident.Name
+
+			// Skip if all lowercase or all uppercase
+			if name == strings.ToLower(name) || name == strings.ToUpper(name) {
+				return true
+			}
+
+			// Split into camel case parts
+			parts := splitCamelCase(name)
+
+			// Only check if there are multiple parts
+			if len(parts) < 2 {
+				return true
+			}
+
+			for i, part := range parts {
+				lower := strings.ToLower(part)
+				if correct, exists := acronymMap[lower]; exists && part != correct {
+					// Allow acronym if it's the first part and lowercase (e.g., dutPorts)
+					if i == 0 && part == lower {
+						continue
+					}
+					// Acronyms starting in lowercase, such as gNMI, are written
+					// in capitals after the first part (e.g., newGNMIClient).
+					if unicode.IsLower([]rune(correct)[0]) {
+						if part == strings.ToUpper(correct) {
+							continue
+						}
+						correct = strings.ToUpper(correct)
+					}
+					pos := fs.Position(ident.Pos())
+					report(errs, "acronym", pos, "improper acronym casing in identifier '%s', should use '%s' instead of '%s'", name, correct, part)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// splitCamelCase splits a camelCase or PascalCase string into its components.
+func splitCamelCase(s string) []string {
+	var parts []string
+	runes := []rune(s)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsUpper(runes[i]) && (i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+	parts = append(parts, string(runes[start:]))
+	return parts
+}
+
+// mixedCapsRegex matches names starting in lowercase that contain at least
+// one uppercase letter.
+var mixedCapsRegex = regexp.MustCompile(`^[a-z]+[A-Z][A-Za-z0-9]*$`)
+
+func validateMixedCaps(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		decl, ok := n.(*ast.GenDecl)
+		if !ok || decl.Tok != token.VAR {
+			return true
+		}
+
+		for _, spec := range decl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for _, name := range valueSpec.Names {
+				if !mixedCapsRegex.MatchString(name.Name) {
+					pos := fs.Position(name.Pos())
+					report(errs, "var-mixed-caps", pos, "variable '%s' does not follow MixedCaps (e.g., otgAgg1)", name.Name)
+				}
+			}
+		}
+		return true
+	})
+}
+
+var kPrefix = regexp.MustCompile(`^k[A-Z0-9]`)
+
+// commonInitialisms are the initialisms a constant may be named after in
+// full, e.g. const TTL = 64, besides the configured acronyms.
+var commonInitialisms = []string{
+	"ACL", "API", "ASCII", "ASN", "BGP", "CPU", "DNS", "EOF", "GUID", "HTML",
+	"HTTP", "HTTPS", "ID", "ISIS", "JSON", "LACP", "LLDP", "MTU", "QPS",
+	"RAM", "RPC", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID",
+	"URI", "URL", "UTF8", "UUID", "VLAN", "VM", "VRF", "XML",
+}
+
+// validateConstNames flags constants named in ALL_CAPS, snake_case or with a
+// k prefix, and related constants declared outside a shared const block.
+func (v *Validator) validateConstNames(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	// firstUngrouped remembers, per leading name word, the first constant
+	// declared outside a const block.
+	firstUngrouped := make(map[string]string)
+
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range gd.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for _, name := range vs.Names {
+				if name.Name == "_" {
+					continue
+				}
+				pos := fs.Position(name.Pos())
+
+				if strings.Contains(name.Name, "_") || v.allCaps(name.Name) {
+					report(errs, "const-name", pos, "constant %s should use MixedCaps, not ALL_CAPS or snake_case", name.Name)
+				}
+				if kPrefix.MatchString(name.Name) {
+					report(errs, "const-name", pos, "constant %s should not use a k prefix", name.Name)
+				}
+
+				if gd.Lparen.IsValid() {
+					continue
+				}
+				key := strings.ToLower(leadingWord(name.Name))
+				if first, ok := firstUngrouped[key]; ok {
+					suggest(errs, "const-grouping", pos, "declare related constants in a single const block", "constant %s is related to %s", name.Name, first)
+					continue
+				}
+				firstUngrouped[key] = name.Name
+			}
+		}
+	}
+}
+
+// allCaps reports whether name, e.g. TIMEOUT, is in upper case throughout
+// and longer than one letter without being made of known initialisms, as
+// MTU or HTTPURL are.
+func (v *Validator) allCaps(name string) bool {
+	if len(name) < 2 || strings.ToUpper(name) != name || strings.ToLower(name) == name {
+		return false
+	}
+	known := slices.Concat(commonInitialisms, v.acronyms)
+	// initialisms[i] is whether name[:i] is a run of known initialisms.
+	initialisms := make([]bool, len(name)+1)
+	initialisms[0] = true
+	for i := range name {
+		if !initialisms[i] {
+			continue
+		}
+		for _, word := range known {
+			if strings.HasPrefix(name[i:], word) {
+				initialisms[i+len(word)] = true
+			}
+		}
+		// Digits may follow, as in TLS13.
+		if i > 0 && unicode.IsDigit(rune(name[i])) {
+			initialisms[i+1] = true
+		}
+	}
+	return !initialisms[len(name)]
+}
+
+// leadingWord returns the first word of a MixedCaps name, e.g. "bgp" for
+// "bgpASN" and "MTU" for "MTUSize".
+func leadingWord(name string) string {
+	runes := []rune(name)
+	for i := 1; i < len(runes); i++ {
+		prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsUpper(runes[i]) && (prevLower || nextLower) {
+			return string(runes[:i])
+		}
+	}
+	return name
+}
+
+func validateTestFileStructure(path string, f *ast.File, errs *[]Issue) {
+	var testFuncs []*ast.FuncDecl
+
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Type == nil || fn.Body == nil {
+			continue
+		}
+
+		// TestMain is checked per package by validateTestMains.
+		if fn.Name.Name == "TestMain" {
+			continue
+		}
+
+		// Collect test functions
+		if strings.HasPrefix(fn.Name.Name, "Test") {
+			testFuncs = append(testFuncs, fn)
+		}
+	}
+
+	if len(testFuncs) == 0 {
+		report(errs, "test-structure", token.Position{Filename: path}, "no test functions found")
+		return
+	}
+
+	if len(testFuncs) > 1 {
+		suggest(errs, "test-structure", token.Position{Filename: path}, "please follow table-driven approach ref: https://go.dev/wiki/TableDrivenTests", "multiple top-level test functions found")
+	}
+
+	// Validate the single allowed test function
+	mainTest := testFuncs[0]
+	var hasSliceDecl, hasForLoop bool
+
+	ast.Inspect(mainTest.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.DeclStmt:
+			if genDecl, ok := stmt.Decl.(*ast.GenDecl); ok {
+				for _, spec := range genDecl.Specs {
+					if vs, ok := spec.(*ast.ValueSpec); ok {
+						// Case 1: Explicit slice type
+						if _, ok := vs.Type.(*ast.ArrayType); ok {
+							hasSliceDecl = true
+						}
+
+						// Case 2: Inferred slice type via composite literal
+						if vs.Type == nil {
+							for _, val := range vs.Values {
+								if cl, ok := val.(*ast.CompositeLit); ok {
+									switch cl.Type.(type) {
+									case *ast.ArrayType, *ast.Ident, *ast.SelectorExpr:
+										hasSliceDecl = true
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		case *ast.AssignStmt:
+			// Handle inferred slice via := assignment
+			for _, rhs := range stmt.Rhs {
+				if cl, ok := rhs.(*ast.CompositeLit); ok {
+					switch cl.Type.(type) {
+					case *ast.ArrayType, *ast.Ident, *ast.SelectorExpr:
+						hasSliceDecl = true
+					}
+				}
+			}
+		case *ast.RangeStmt:
+			hasForLoop = true
+		}
+		return true
+	})
+
+	if !(hasSliceDecl && hasForLoop) {
+		report(errs, "test-structure", token.Position{Filename: path}, "test function %s does not follow table-driven test pattern. Please follow table driven approach ref: https://go.dev/wiki/TableDrivenTests", mainTest.Name.Name)
+	}
+}
+
+func (v *Validator) validateTestImports(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	rules := v.cfg.TestImports
+	if !matchAnyGlob(rules.Files, path) {
+		return
+	}
+
+	for _, imp := range f.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		pos := fs.Position(imp.Pos())
+
+		if matchAnyGlob(rules.Deny, importPath) {
+			suggest(errs, "test-imports", pos, "move shared code into a helper package instead of coupling test suites", "test file must not import %q", importPath)
+			continue
+		}
+
+		// The standard library is always allowed.
+		if len(rules.Allow) == 0 || !strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			continue
+		}
+		if !matchAnyGlob(rules.Allow, importPath) {
+			report(errs, "test-imports", pos, "test file imports %q, which is not in the allowed import list", importPath)
+		}
+	}
+}
+
+// validateImportVisibility flags imports of internal packages from outside
+// the tree rooted at the parent of their internal directory, and imports
+// of packages whose importers are restricted by the config.
+func (v *Validator) validateImportVisibility(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	importer := packageImportPath(filepath.Dir(path))
+
+	for _, imp := range f.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		pos := fs.Position(imp.Pos())
+
+		if parent, ok := internalParent(importPath); ok && importer != "" && !withinPackage(importer, parent) {
+			report(errs, "import-visibility", pos, "%q is internal to %s and must not be imported from %s", importPath, parent, importer)
+			continue
+		}
+
+		for _, rule := range v.cfg.Visibility {
+			if !matchAnyGlob(rule.Packages, importPath) || withinPackage(importer, importPath) {
+				continue
+			}
+			if !matchAnyGlob(rule.Files, path) {
+				report(errs, "import-visibility", pos, "%q may only be imported from files matching %s", importPath, strings.Join(rule.Files, ", "))
+				break
+			}
+		}
+	}
+}
+
+// internalParent returns the import path under which an internal package
+// is visible, e.g. "a/b" for "a/b/internal/c".
+func internalParent(importPath string) (string, bool) {
+	if strings.HasPrefix(importPath, "internal/") || importPath == "internal" {
+		return "", true
+	}
+	if i := strings.LastIndex(importPath, "/internal/"); i >= 0 {
+		return importPath[:i], true
+	}
+	if strings.HasSuffix(importPath, "/internal") {
+		return strings.TrimSuffix(importPath, "/internal"), true
+	}
+	return "", false
+}
+
+// withinPackage reports whether importPath is pkg or one of its subpackages.
+func withinPackage(importPath, pkg string) bool {
+	return importPath != "" && (pkg == "" || importPath == pkg || strings.HasPrefix(importPath, pkg+"/"))
+}
+
+func validateSharedTestHelpers(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	var ordered []*ast.FuncDecl
+	helpers := make(map[string]*ast.FuncDecl)
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !fn.Name.IsExported() || isTestEntryPoint(fn.Name.Name) {
+			continue
+		}
+		ordered = append(ordered, fn)
+		helpers[fn.Name.Name] = fn
+	}
+	if len(helpers) == 0 {
+		return
+	}
+
+	// Count the test files of the package that refer to each helper.
+	users := make(map[string][]string)
+	files := append([]*ast.File{f}, parseSiblings(fs, path, f.Name.Name)...)
+	for _, file := range files {
+		filename := fs.Position(file.Package).Filename
+		if !isTestFile(filename) {
+			continue
+		}
+		seen := make(map[string]bool)
+		ast.Inspect(file, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok || helpers[ident.Name] == nil || seen[ident.Name] || ident == helpers[ident.Name].Name {
+				return true
+			}
+			seen[ident.Name] = true
+			users[ident.Name] = append(users[ident.Name], filepath.Base(filename))
+			return true
+		})
+	}
+
+	for _, fn := range ordered {
+		name := fn.Name.Name
+		if len(users[name]) < 2 {
+			continue
+		}
+		pos := fs.Position(fn.Pos())
+		suggest(errs, "shared-test-helper", pos, "move it into the suite's shared helpers or cfgplugins package", "exported helper %s is used by %d test files (%s)", name, len(users[name]), strings.Join(users[name], ", "))
+	}
+}
+
+// isTestEntryPoint reports whether name is run by the testing package.
+func isTestEntryPoint(name string) bool {
+	for _, prefix := range []string{"Test", "Benchmark", "Example", "Fuzz"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// declaredDurationRE matches duration declarations such as "Duration: 30m"
+// in a README or `timeout: "45m"` in metadata.textproto.
+var declaredDurationRE = regexp.MustCompile(`(?im)^[\s*#-]*(?:test[ _])?(?:duration|timeout)\s*[:=]\s*"?([0-9][0-9a-zµ.]*)"?`)
+
+func (v *Validator) validateTestTimeBudget(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	var testMain *ast.FuncDecl
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "TestMain" {
+			testMain = fn
+		}
+	}
+	// The budget is checked once per test, from the file holding TestMain.
+	if testMain == nil {
+		return
+	}
+
+	budget, source := declaredTestDuration(filepath.Dir(path))
+	if budget == 0 {
+		budget, source = v.testBudget, "config default"
+	}
+	if budget == 0 {
+		return
+	}
+
+	files := []*ast.File{f}
+	for _, sibling := range parseSiblings(fs, path, f.Name.Name) {
+		if isTestFile(fs.Position(sibling.Package).Filename) {
+			files = append(files, sibling)
+		}
+	}
+	typesInfo := typeCheckFiles(fs, files)
+
+	var total time.Duration
+	for _, file := range files {
+		total += sumDeclaredWaits(typesInfo, file, 1)
+	}
+
+	if total > budget {
+		pos := fs.Position(testMain.Pos())
+		report(errs, "test-budget", pos, "declared waits add up to %v, exceeding the %v test budget (%s)", total, budget, source)
+	}
+}
+
+// declaredTestDuration returns the duration declared in the README.md or
+// metadata.textproto of a test directory, and where it was found.
+func declaredTestDuration(dir string) (time.Duration, string) {
+	for _, name := range []string{"README.md", "metadata.textproto"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		m := declaredDurationRE.FindSubmatch(data)
+		if m == nil {
+			continue
+		}
+		if d, err := time.ParseDuration(string(m[1])); err == nil {
+			return d, name
+		}
+	}
+	return 0, ""
+}
+
+// sumDeclaredWaits adds up the constant durations passed to sleeps, awaits,
+// watches and timeouts under node. Waits inside loops with a constant trip
+// count, i.e. retry loops, are multiplied by that count.
+func sumDeclaredWaits(typesInfo *types.Info, node ast.Node, multiplier int64) time.Duration {
+	var total time.Duration
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.ForStmt:
+			if trips := constantTripCount(typesInfo, stmt); trips > 0 && n != node {
+				total += sumDeclaredWaits(typesInfo, stmt.Body, multiplier*trips)
+				return false
+			}
+		case *ast.RangeStmt:
+			if tv, ok := typesInfo.Types[stmt.X]; ok && tv.Value != nil && n != node {
+				if trips, ok := constant.Int64Val(tv.Value); ok && trips > 0 {
+					total += sumDeclaredWaits(typesInfo, stmt.Body, multiplier*trips)
+					return false
+				}
+			}
+		case *ast.CallExpr:
+			if !isWaitCall(stmt) {
+				return true
+			}
+			for _, arg := range stmt.Args {
+				if d, ok := constantDuration(typesInfo, arg); ok {
+					total += time.Duration(multiplier) * d
+					break
+				}
+			}
+		}
+		return true
+	})
+
+	return total
+}
+
+// isWaitCall reports whether call blocks for a caller-supplied duration.
+func isWaitCall(call *ast.CallExpr) bool {
+	var name string
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		name = fun.Name
+	case *ast.SelectorExpr:
+		name = fun.Sel.Name
+	case *ast.IndexExpr:
+		if sel, ok := fun.X.(*ast.SelectorExpr); ok {
+			name = sel.Sel.Name
+		}
+	}
+
+	switch name {
+	case "Sleep", "Await", "AwaitTimeout", "Watch", "WithTimeout":
+		return true
+	}
+	return false
+}
+
+// constantDuration returns the value of expr when it is a constant of type
+// time.Duration.
+func constantDuration(typesInfo *types.Info, expr ast.Expr) (time.Duration, bool) {
+	tv, ok := typesInfo.Types[expr]
+	if !ok || tv.Value == nil || !isTimeDuration(tv.Type) {
+		return 0, false
+	}
+	v, ok := constant.Int64Val(constant.ToInt(tv.Value))
+	return time.Duration(v), ok
+}
+
+// isTimeDuration reports whether t is time.Duration.
+func isTimeDuration(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Duration"
+}
+
+// constantTripCount returns N for loops shaped like "for i := 0; i < N; i++"
+// with a constant N, and 0 otherwise.
+func constantTripCount(typesInfo *types.Info, loop *ast.ForStmt) int64 {
+	cond, ok := loop.Cond.(*ast.BinaryExpr)
+	if !ok || (cond.Op != token.LSS && cond.Op != token.LEQ) {
+		return 0
+	}
+	tv, ok := typesInfo.Types[cond.Y]
+	if !ok || tv.Value == nil {
+		return 0
+	}
+	n, ok := constant.Int64Val(constant.ToInt(tv.Value))
+	if !ok {
+		return 0
+	}
+	if cond.Op == token.LEQ {
+		n++
+	}
+	return n
+}
+
+func validateUnusedTableFields(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	// Struct types declared in the file may be used as table element types.
+	structTypes := make(map[string]*ast.StructType)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if ts, ok := n.(*ast.TypeSpec); ok {
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				structTypes[ts.Name.Name] = st
+			}
+		}
+		return true
+	})
+
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || !strings.HasPrefix(fn.Name.Name, "Test") {
+			continue
+		}
+
+		// Collect the test tables declared in the function.
+		tables := make(map[string]*ast.StructType)
+		addTable := func(name *ast.Ident, value ast.Expr) {
+			lit, ok := value.(*ast.CompositeLit)
+			if !ok {
+				return
+			}
+			var elt ast.Expr
+			switch t := lit.Type.(type) {
+			case *ast.ArrayType:
+				elt = t.Elt
+			case *ast.MapType:
+				elt = t.Value
+			default:
+				return
+			}
+			switch e := elt.(type) {
+			case *ast.StructType:
+				tables[name.Name] = e
+			case *ast.Ident:
+				if st, ok := structTypes[e.Name]; ok {
+					tables[name.Name] = st
+				}
+			}
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch stmt := n.(type) {
+			case *ast.AssignStmt:
+				for i, lhs := range stmt.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok && i < len(stmt.Rhs) {
+						addTable(ident, stmt.Rhs[i])
+					}
+				}
+			case *ast.ValueSpec:
+				for i, name := range stmt.Names {
+					if i < len(stmt.Values) {
+						addTable(name, stmt.Values[i])
+					}
+				}
+			}
+			return true
+		})
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			loop, ok := n.(*ast.RangeStmt)
+			if !ok {
+				return true
+			}
+			table, ok := loop.X.(*ast.Ident)
+			if !ok || tables[table.Name] == nil {
+				return true
+			}
+			caseVar, ok := loop.Value.(*ast.Ident)
+			if !ok || caseVar.Name == "_" {
+				return true
+			}
+
+			used := make(map[string]bool)
+			escapes := false
+			ast.Inspect(loop.Body, func(n ast.Node) bool {
+				if sel, ok := n.(*ast.SelectorExpr); ok {
+					if x, ok := sel.X.(*ast.Ident); ok && x.Name == caseVar.Name {
+						used[sel.Sel.Name] = true
+						return false
+					}
+				}
+				// The whole case is handed on, e.g. to a helper; its fields
+				// may be read there.
+				if ident, ok := n.(*ast.Ident); ok && ident.Name == caseVar.Name {
+					escapes = true
+				}
+				return true
+			})
+			if escapes {
+				return true
+			}
+
+			for _, field := range tables[table.Name].Fields.List {
+				for _, name := range field.Names {
+					if used[name.Name] {
+						continue
+					}
+					pos := fs.Position(name.Pos())
+					suggest(errs, "unused-table-field", pos, "remove it", "test table field %q is never used in the loop over %s", name.Name, table.Name)
+				}
+			}
+			return true
+		})
+	}
+}
+
+func (v *Validator) validateWantGotNames(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	cfg := v.cfg.WantGot
+	if cfg.Want == "" {
+		cfg.Want = "want"
+	}
+	if cfg.Got == "" {
+		cfg.Got = "got"
+	}
+	if len(cfg.Expected) == 0 {
+		cfg.Expected = []string{"expected", "expect", "exp"}
+	}
+	if len(cfg.Actual) == 0 {
+		cfg.Actual = []string{"actual", "act"}
+	}
+
+	check := func(name *ast.Ident) {
+		if prefix := namePrefix(name.Name, cfg.Expected); prefix != "" {
+			pos := fs.Position(name.Pos())
+			suggest(errs, "want-got", pos, fmt.Sprintf("name it %s", renamePrefix(name.Name, prefix, cfg.Want)), "variable %s holds an expected value", name.Name)
+		} else if prefix := namePrefix(name.Name, cfg.Actual); prefix != "" {
+			pos := fs.Position(name.Pos())
+			suggest(errs, "want-got", pos, fmt.Sprintf("name it %s", renamePrefix(name.Name, prefix, cfg.Got)), "variable %s holds an actual value", name.Name)
+		}
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			if stmt.Tok != token.DEFINE {
+				return true
+			}
+			for _, lhs := range stmt.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					check(ident)
+				}
+			}
+		case *ast.ValueSpec:
+			for _, name := range stmt.Names {
+				check(name)
+			}
+		}
+		return true
+	})
+}
+
+// namePrefix returns the entry of prefixes that name starts with as a whole
+// word, ignoring case, e.g. "expected" for "expectedRoutes" but not for
+// "expectedness".
+func namePrefix(name string, prefixes []string) string {
+	for _, p := range prefixes {
+		if len(name) < len(p) || !strings.EqualFold(name[:len(p)], p) {
+			continue
+		}
+		if len(name) == len(p) {
+			return p
+		}
+		if next := rune(name[len(p)]); unicode.IsUpper(next) || unicode.IsDigit(next) || next == '_' {
+			return p
+		}
+	}
+	return ""
+}
+
+// renamePrefix replaces the leading prefix of name with replacement, keeping
+// the case of the first letter.
+func renamePrefix(name, prefix, replacement string) string {
+	if unicode.IsUpper(rune(name[0])) {
+		replacement = strings.ToUpper(replacement[:1]) + replacement[1:]
+	}
+	return replacement + name[len(prefix):]
+}
+
+// validateTestLogCalls checks that t.Log gets a single argument and t.Logf
+// a format string with arguments. Calls are matched on the AST, so calls
+// spanning several lines, on any *testing.T variable and with raw string
+// formats are all covered.
+func validateTestLogCalls(path string, fs *token.FileSet, f *ast.File, typesInfo *types.Info, errs *[]Issue) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (sel.Sel.Name != "Log" && sel.Sel.Name != "Logf") || !isTestingValue(typesInfo, sel.X) {
+			return true
+		}
+
+		pos := fs.Position(call.Pos())
+		src := types.ExprString(call)
+		switch sel.Sel.Name {
+		case "Log":
+			if len(call.Args) > 1 {
+				report(errs, "t-log-args", pos, "t.Log() should not use multiple arguments: %s, instead use t.Logf()", src)
+			}
+		case "Logf":
+			if len(call.Args) != 1 || call.Ellipsis.IsValid() {
+				return true
+			}
+			// Formats with verbs are missing arguments; format-verbs reports those.
+			if format, ok := constantString(typesInfo, call.Args[0]); ok && len(parseFormat(format)) > 0 {
+				return true
+			}
+			report(errs, "t-logf-args", pos, "t.Logf() must have arguments after format string: %s, instead use t.Log()", src)
+		}
+		return true
+	})
+}
+
+// isTestingValue reports whether expr is a *testing.T, *testing.B,
+// *testing.F or testing.TB. Without type information it falls back to the
+// declared type of the parameter expr names.
+func isTestingValue(typesInfo *types.Info, expr ast.Expr) bool {
+	if t := typesInfo.TypeOf(expr); t != nil && t != types.Typ[types.Invalid] {
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		named, ok := t.(*types.Named)
+		return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "testing"
+	}
+
+	ident, ok := expr.(*ast.Ident)
+	if !ok || ident.Obj == nil {
+		return false
+	}
+	field, ok := ident.Obj.Decl.(*ast.Field)
+	if !ok {
+		return false
+	}
+	typ := field.Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	typeSel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := typeSel.X.(*ast.Ident)
+	return ok && pkg.Name == "testing"
+}
+
+// constantString returns the value of expr when it is a constant string,
+// including raw string literals.
+func constantString(typesInfo *types.Info, expr ast.Expr) (string, bool) {
+	if tv, ok := typesInfo.Types[expr]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		return constant.StringVal(tv.Value), true
+	}
+	if lit, ok := ast.Unparen(expr).(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		s, err := strconv.Unquote(lit.Value)
+		return s, err == nil
+	}
+	return "", false
+}
+
+// validateFormatVerbs checks the format strings of printf-style calls such as
+// fmt.Sprintf, t.Errorf and t.Logf against their arguments: the number of
+// arguments must match the verbs and each argument must suit its verb.
+func validateFormatVerbs(path string, fs *token.FileSet, f *ast.File, typesInfo *types.Info, errs *[]Issue) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || call.Ellipsis.IsValid() {
+			return true
+		}
+		idx := formatParamIndex(typesInfo, call)
+		if idx < 0 || idx >= len(call.Args) {
+			return true
+		}
+		format, ok := constantString(typesInfo, call.Args[idx])
+		if !ok {
+			return true
+		}
+
+		name := types.ExprString(call.Fun)
+		args := call.Args[idx+1:]
+		pos := fs.Position(call.Pos())
+		verbs := parseFormat(format)
+
+		want, indexed := 0, false
+		for _, v := range verbs {
+			want = max(want, v.Arg+1)
+			indexed = indexed || v.Indexed
+		}
+		if want > len(args) || (want < len(args) && !indexed) {
+			report(errs, "format-verbs", pos, "%s format %q reads %d arguments, but the call has %d", name, format, want, len(args))
+		}
+
+		for _, v := range verbs {
+			if v.Arg >= len(args) {
+				continue
+			}
+			arg := args[v.Arg]
+			if msg := checkVerbArg(typesInfo, v.Verb, arg); msg != "" {
+				report(errs, "format-verbs", fs.Position(arg.Pos()), "%s format %%%c %s: %s", name, v.Verb, msg, types.ExprString(arg))
+			}
+		}
+		return true
+	})
+}
+
+// formatParamIndex returns the index of the format parameter of the function
+// called, or -1 when it is not printf-style. A function is printf-style when
+// it is variadic over any and its last fixed parameter is a string named
+// format, as in the fmt, log and testing packages.
+func formatParamIndex(typesInfo *types.Info, call *ast.CallExpr) int {
+	var ident *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return -1
+	}
+	fn, ok := typesInfo.Uses[ident].(*types.Func)
+	if !ok {
+		return -1
+	}
+	sig := fn.Type().(*types.Signature)
+	params := sig.Params()
+	if !sig.Variadic() || params.Len() < 2 {
+		return -1
+	}
+	last := params.At(params.Len() - 1).Type().(*types.Slice)
+	if iface, ok := last.Elem().Underlying().(*types.Interface); !ok || !iface.Empty() {
+		return -1
+	}
+	format := params.At(params.Len() - 2)
+	if format.Name() != "format" || !types.Identical(format.Type(), types.Typ[types.String]) {
+		return -1
+	}
+	return params.Len() - 2
+}
+
+// formatVerb is one directive of a printf format string.
+type formatVerb struct {
+	Verb rune
+	// Arg is the index of the argument the verb reads.
+	Arg int
+	// Indexed is set when the directive uses an explicit [n] index.
+	Indexed bool
+}
+
+// parseFormat returns the directives of a printf format string in order.
+// A '*' width or precision reads an int argument and is returned as verb '*'.
+// "%%" is not a directive.
+func parseFormat(format string) []formatVerb {
+	var verbs []formatVerb
+	arg := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		indexed := false
+		// argIndex consumes an explicit [n] argument index.
+		argIndex := func() {
+			if i >= len(format) || format[i] != '[' {
+				return
+			}
+			end := strings.IndexByte(format[i:], ']')
+			if end < 0 {
+				return
+			}
+			if n, err := strconv.Atoi(format[i+1 : i+end]); err == nil && n > 0 {
+				arg, indexed = n-1, true
+			}
+			i += end + 1
+		}
+		// number consumes a width or precision.
+		number := func() {
+			argIndex()
+			if i < len(format) && format[i] == '*' {
+				verbs = append(verbs, formatVerb{Verb: '*', Arg: arg, Indexed: indexed})
+				arg++
+				i++
+				return
+			}
+			for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+				i++
+			}
+		}
+
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++
+		}
+		number()
+		if i < len(format) && format[i] == '.' {
+			i++
+			number()
+		}
+		argIndex()
+		if i >= len(format) {
+			break
+		}
+		if format[i] == '%' {
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(format[i:])
+		verbs = append(verbs, formatVerb{Verb: r, Arg: arg, Indexed: indexed})
+		arg++
+		i += size - 1
+	}
+	return verbs
+}
+
+// verbKinds lists, for the verbs checked, the basic type kinds they accept.
+var verbKinds = map[rune]types.BasicInfo{
+	'*': types.IsInteger,
+	'b': types.IsInteger | types.IsFloat | types.IsComplex,
+	'c': types.IsInteger,
+	'd': types.IsInteger,
+	'o': types.IsInteger,
+	'O': types.IsInteger,
+	'U': types.IsInteger,
+	'x': types.IsInteger | types.IsFloat | types.IsComplex | types.IsString,
+	'X': types.IsInteger | types.IsFloat | types.IsComplex | types.IsString,
+	'e': types.IsFloat | types.IsComplex,
+	'E': types.IsFloat | types.IsComplex,
+	'f': types.IsFloat | types.IsComplex,
+	'F': types.IsFloat | types.IsComplex,
+	'g': types.IsFloat | types.IsComplex,
+	'G': types.IsFloat | types.IsComplex,
+	's': types.IsString,
+	'q': types.IsString | types.IsInteger,
+	't': types.IsBoolean,
+}
+
+// checkVerbArg describes why arg does not suit verb, or returns "" when it
+// does or its type is unknown. Only arguments of basic type are checked;
+// types with String, Error or Format methods are left to their methods.
+func checkVerbArg(typesInfo *types.Info, verb rune, arg ast.Expr) string {
+	if verb == 'v' || verb == 'T' || verb == 'p' || verb == 'w' {
+		return ""
+	}
+	kinds, ok := verbKinds[verb]
+	if !ok {
+		return "is not a known verb"
+	}
+	t := typesInfo.TypeOf(arg)
+	if t == nil {
+		return ""
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok || basic.Kind() == types.Invalid || basic.Kind() == types.UntypedNil {
+		return ""
+	}
+	if verb != '*' {
+		for _, method := range []string{"String", "Error", "Format"} {
+			if obj, _, _ := types.LookupFieldOrMethod(t, true, nil, method); obj != nil {
+				return ""
+			}
+		}
+	}
+	if basic.Info()&kinds == 0 {
+		return fmt.Sprintf("given %s", t)
+	}
+	return ""
+}
+
+// timeCallMatcher returns a func reporting whether an expression calls the
+// named function of package time as f imports it. Without type information
+// the import name alone decides; with it, a variable shadowing the package
+// name does not count.
+func timeCallMatcher(f *ast.File, typesInfo *types.Info) func(ast.Expr, string) bool {
+	timePkg := localImportName(f, "time")
+	return func(expr ast.Expr, name string) bool {
+		if !isPkgCall(expr, timePkg, name) {
+			return false
+		}
+		pkg := ast.Unparen(expr).(*ast.CallExpr).Fun.(*ast.SelectorExpr).X.(*ast.Ident)
+		obj, ok := typesInfo.Uses[pkg]
+		if !ok {
+			return true
+		}
+		pkgName, ok := obj.(*types.PkgName)
+		return ok && pkgName.Imported().Path() == "time"
+	}
+}
+
+// validateSleepEquivalents extends the time.Sleep ban to the waits written
+// to get around it: receiving from time.After outside a select, where it
+// only sleeps; time.Tick, whose ticker can never be stopped; time.NewTicker
+// tickers the function never stops; and the wait helpers listed under
+// sleep.equivalents in the config.
+func (v *Validator) validateSleepEquivalents(fs *token.FileSet, f *ast.File, typesInfo *types.Info, errs *[]Issue) {
+	isTimeCall := timeCallMatcher(f, typesInfo)
+
+	// Receives in select cases time out the other cases.
+	timeouts := make(map[ast.Expr]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		clause, ok := n.(*ast.CommClause)
+		if !ok || clause.Comm == nil {
+			return true
+		}
+		ast.Inspect(clause.Comm, func(n ast.Node) bool {
+			if recv, ok := n.(*ast.UnaryExpr); ok && recv.Op == token.ARROW {
+				timeouts[recv] = true
+			}
+			return true
+		})
+		return true
+	})
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW && !timeouts[n] && isTimeCall(n.X, "After") {
+				suggest(errs, "sleep-equivalents", fs.Position(n.Pos()), "use gnmi.Watch or gnmi.Await", "receiving from time.After outside a select sleeps like time.Sleep")
+			}
+		case *ast.CallExpr:
+			if isTimeCall(n, "Tick") {
+				suggest(errs, "sleep-equivalents", fs.Position(n.Pos()), "use gnmi.Watch or gnmi.Await", "time.Tick polls on a ticker that is never stopped")
+				return true
+			}
+			if name := types.ExprString(n.Fun); slices.Contains(v.cfg.Sleep.Equivalents, name) {
+				suggest(errs, "sleep-equivalents", fs.Position(n.Pos()), "use gnmi.Watch or gnmi.Await", "%s waits a fixed time like time.Sleep", name)
+			}
+		case *ast.FuncDecl:
+			if n.Body != nil {
+				validateTickerStops(fs, n.Body, typesInfo, isTimeCall, errs)
+			}
+		}
+		return true
+	})
+}
+
+// validateTickerStops flags time.NewTicker tickers assigned to a variable in
+// body that body never calls Stop on. Tickers that leave body, returned,
+// stored or passed to a call, are left to whoever receives them.
+func validateTickerStops(fs *token.FileSet, body *ast.BlockStmt, typesInfo *types.Info, isTimeCall func(ast.Expr, string) bool, errs *[]Issue) {
+	var tickers []*ast.Ident
+	ast.Inspect(body, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok && len(assign.Lhs) == len(assign.Rhs) {
+			for i, rhs := range assign.Rhs {
+				if ident, ok := assign.Lhs[i].(*ast.Ident); ok && ident.Name != "_" && typesInfo.ObjectOf(ident) != nil && isTimeCall(rhs, "NewTicker") {
+					tickers = append(tickers, ident)
+				}
+			}
+		}
+		return true
+	})
+	if len(tickers) == 0 {
+		return
+	}
+
+	// handled holds the tickers stopped in body or escaping it.
+	handled := make(map[types.Object]bool)
+	handle := func(exprs ...ast.Expr) {
+		for _, expr := range exprs {
+			if kv, ok := expr.(*ast.KeyValueExpr); ok {
+				expr = kv.Value
+			}
+			if ident, ok := ast.Unparen(expr).(*ast.Ident); ok {
+				if obj := typesInfo.ObjectOf(ident); obj != nil {
+					handled[obj] = true
+				}
+			}
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if sel, ok := n.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Stop" {
+				handle(sel.X)
+			}
+			handle(n.Args...)
+		case *ast.ReturnStmt:
+			handle(n.Results...)
+		case *ast.AssignStmt:
+			handle(n.Rhs...)
+		case *ast.ValueSpec:
+			handle(n.Values...)
+		case *ast.CompositeLit:
+			handle(n.Elts...)
+		case *ast.SendStmt:
+			handle(n.Value)
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				handle(n.X)
+			}
+		}
+		return true
+	})
+	for _, t := range tickers {
+		if !handled[typesInfo.ObjectOf(t)] {
+			suggest(errs, "sleep-equivalents", fs.Position(t.Pos()), fmt.Sprintf("defer %s.Stop() or use gnmi.Watch", t.Name), "ticker %s is never stopped, so it keeps firing after the wait", t.Name)
+		}
+	}
+}
+
+// Rule 9 & 18 scans
+func (v *Validator) scanFileForPatterns(path string) []Issue {
+	var errs []Issue
+	v.forEachLine(path, &errs, func(lineNo int, line string) {
+		// Rule 9: ban time.Sleep
+		if strings.Contains(line, "time.Sleep(") {
+			report(&errs, "time-sleep", token.Position{Filename: path, Line: lineNo}, "avoid time.Sleep, use gnmi.Watch")
+		}
+		// Rule 18: cfgplugin funcs must return gnmi.SetRequest / Batch object.
+		// The rule is scoped to cfgplugins files through defaultRuleConfigs.
+		if strings.Contains(line, "func") && strings.Contains(line, "{") {
+			if !strings.Contains(line, "gnmi.SetRequest") && !strings.Contains(line, "gnmi.Batch") {
+				report(&errs, "cfgplugin-return", token.Position{Filename: path, Line: lineNo}, "cfgplugin function should return gnmi Batch/SetRequest")
+			}
+		}
+		// StringPiecelMeal: multiple string concatenation
+		if strings.Contains(line, `" + "`) {
+			report(&errs, "string-concat", token.Position{Filename: path, Line: lineNo}, "avoid piecing strings with '+', use fmt.Sprintf or strings.Builder")
+		}
+
+		// ErrorStrings: idiomatic error strings
+		if strings.Contains(line, "t.Errorf(") || strings.Contains(line, "t.Error(") || strings.Contains(line, "fmt.Errorf(") {
+			msg := extractStringLiteral(line)
+			if msg != "" {
+				if strings.HasPrefix(msg, strings.ToUpper(msg[:1])) {
+					report(&errs, "error-string", token.Position{Filename: path, Line: lineNo}, "error string should not be capitalized")
+				}
+				if strings.HasSuffix(msg, ".") {
+					report(&errs, "error-string", token.Position{Filename: path, Line: lineNo}, "error string should not end with '.'")
+				}
+			}
+		}
+	})
+	return errs
+}
+
+// bareBugRe matches bare bug IDs like: "sample b/123456789"
+var bareBugRe = regexp.MustCompile(`\b\w+\s+b/(\d{9})\b`)
+
+// Rule 20: proto file must include bug URL
+func (v *Validator) checkProtoFiles(ctx context.Context, root string) []Issue {
+	var errs []Issue
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if ctx.Err() != nil {
+			return filepath.SkipAll
+		}
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".proto") || !v.selected(path) {
+			return nil
+		}
+
+		v.forEachLine(path, &errs, func(lineNo int, line string) {
+			matches := bareBugRe.FindStringSubmatch(line)
+			if len(matches) == 2 {
+				// Raise error suggesting full URL
+				report(&errs, "proto-bug-url", token.Position{Filename: path, Line: lineNo}, "found bare bug ID %s, please use full URL like https://example.corp.example.com/issues/%s", matches[1], matches[1])
+			}
+		})
+
+		return nil
+	})
+	return errs
+}
+
+// invisibleRunes are the zero-width and bidirectional control characters
+// flagged in literals; they change what a string means without showing up
+// in an editor or a diff.
+var invisibleRunes = map[rune]string{
+	'\u200b': "zero-width space",
+	'\u200c': "zero-width non-joiner",
+	'\u200d': "zero-width joiner",
+	'\u2060': "word joiner",
+	'\ufeff': "zero-width no-break space",
+	'\u200e': "left-to-right mark",
+	'\u200f': "right-to-left mark",
+	'\u061c': "Arabic letter mark",
+	'\u202a': "left-to-right embedding",
+	'\u202b': "right-to-left embedding",
+	'\u202c': "pop directional formatting",
+	'\u202d': "left-to-right override",
+	'\u202e': "right-to-left override",
+	'\u2066': "left-to-right isolate",
+	'\u2067': "right-to-left isolate",
+	'\u2068': "first strong isolate",
+	'\u2069': "pop directional isolate",
+}
+
+// validateUnicode flags identifiers spelled with non-ASCII characters, such
+// as a Cyrillic "а" that looks like a Latin "a" but fails every grep, and
+// string and rune literals holding invisible characters. Each identifier is
+// reported once per file.
+func validateUnicode(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	seen := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			if seen[n.Name] {
+				return true
+			}
+			for _, r := range n.Name {
+				if r > unicode.MaxASCII {
+					seen[n.Name] = true
+					suggest(errs, "unicode", fs.Position(n.Pos()), "use ASCII identifiers", "identifier %s contains the non-ASCII character %q (%U)", n.Name, r, r)
+					break
+				}
+			}
+		case *ast.BasicLit:
+			if n.Kind != token.STRING && n.Kind != token.CHAR {
+				return true
+			}
+			for i, r := range n.Value {
+				if name, ok := invisibleRunes[r]; ok {
+					pos := fs.Position(n.Pos())
+					pos.Column += i
+					suggest(errs, "unicode", pos, fmt.Sprintf("use the \\u%04x escape if it is intended", r), "literal contains an invisible %s (%U)", name, r)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// metadataPlanRE matches the plan_id and description fields of a
+// metadata.textproto file.
+var metadataPlanRE = regexp.MustCompile(`(?m)^\s*(plan_id|description)\s*:\s*"([^"]*)"`)
+
+// normalizeTestName lowercases s and drops everything but letters and
+// digits, so "TestRT1_1", "RT-1.1" and "rt_1_1_test" compare alike.
+func normalizeTestName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+// validateTestPlanName requires the single top-level test of a feature
+// test, a directory with a metadata.textproto, to be named after its
+// directory or the plan ID or description in the metadata, so go test -run
+// selects tests by plan predictably. Files with several tests are left to
+// test-structure.
+func validateTestPlanName(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	var test *ast.FuncDecl
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Name.Name == "TestMain" || !strings.HasPrefix(fn.Name.Name, "Test") {
+			continue
+		}
+		if test != nil {
+			return
+		}
+		test = fn
+	}
+	if test == nil {
+		return
+	}
+	dir := filepath.Dir(path)
+	data, err := os.ReadFile(filepath.Join(dir, "metadata.textproto"))
+	if err != nil {
+		return
+	}
+
+	base := strings.TrimSuffix(filepath.Base(dir), "_test")
+	names := []string{base}
+	for _, m := range metadataPlanRE.FindAllSubmatch(data, -1) {
+		names = append(names, string(m[2]))
+	}
+	got := normalizeTestName(strings.TrimPrefix(test.Name.Name, "Test"))
+	for _, name := range names {
+		if normalizeTestName(name) == got {
+			return
+		}
+	}
+
+	var want strings.Builder
+	want.WriteString("Test")
+	for _, part := range strings.FieldsFunc(base, func(r rune) bool { return r == '_' || r == '-' }) {
+		want.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	suggest(errs, "test-plan-name", fs.Position(test.Name.Pos()), fmt.Sprintf("name it %s so go test -run finds it by plan", want.String()), "test function %s matches neither its directory %s nor the plan in metadata.textproto", test.Name.Name, filepath.Base(dir))
+}
+
+// metadataUUIDRE matches the uuid field of a metadata.textproto file.
+var metadataUUIDRE = regexp.MustCompile(`(?m)^\s*uuid\s*:\s*"([^"]*)"`)
+
+// checkMetadataUUIDs requires every metadata.textproto under root to carry a
+// uuid that no other metadata file uses; the results pipeline keys on it.
+// seen holds the uuids of the roots checked before, and gains those of root.
+func checkMetadataUUIDs(root string, seen map[string]token.Position) []Issue {
+	var errs []Issue
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || info.Name() != "metadata.textproto" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		m := metadataUUIDRE.FindSubmatchIndex(data)
+		if m == nil || m[3] == m[2] {
+			report(&errs, "metadata-uuid", token.Position{Filename: path}, "metadata is missing a uuid")
+			return nil
+		}
+
+		uuid := string(data[m[2]:m[3]])
+		pos := token.Position{Filename: path, Line: 1 + bytes.Count(data[:m[2]], []byte("\n"))}
+		if first, ok := seen[uuid]; ok {
+			// Overlapping roots walk the same file twice.
+			if sameFile(first.Filename, path) {
+				return nil
+			}
+			report(&errs, "metadata-uuid", pos, "uuid %s is already used by %s:%d", uuid, first.Filename, first.Line)
+			return nil
+		}
+		seen[uuid] = pos
+		return nil
+	})
+	return errs
+}
+
+// sameFile reports whether paths a and b name the same file.
+func sameFile(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
+
+// checkStructParameterUsage enforces struct parameter usage for functions
+func checkStructParameterUsage(path string, fn *ast.FuncDecl, fs *token.FileSet) []Issue {
+	var errs []Issue
+	pos := fs.Position(fn.Pos())
+
+	// Skip empty functions
+	if fn.Type.Params == nil || len(fn.Type.Params.List) == 0 {
+		return errs
+	}
+
+	// Skip if only allowed params (*testing.T, *ondatra.DUTDevice)
+	if len(fn.Type.Params.List) <= 2 && allParamsAllowed(fn.Type.Params.List) {
+		return errs
+	}
+
+	nonStructCount := 0
+	for _, param := range fn.Type.Params.List {
+		typ := param.Type
+		if isAllowedParam(typ) {
+			continue
+		}
+		if !isStructType(typ) && !isPointerToStruct(typ) {
+			nonStructCount++
+		}
+	}
+
+	if nonStructCount > 1 {
+		report(&errs, "struct-param", pos, "function %s has multiple parameters, consider using a single config struct", fn.Name.Name)
+	}
+	return errs
+}
+
+// allParamsAllowed returns true if all params are allowed (*testing.T, *ondatra.DUTDevice)
+func allParamsAllowed(params []*ast.Field) bool {
+	for _, param := range params {
+		if !isAllowedParam(param.Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAllowedParam skips *testing.T and *ondatra.DUTDevice
+func isAllowedParam(expr ast.Expr) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		if sel, ok := star.X.(*ast.SelectorExpr); ok {
+			if sel.Sel.Name == "T" || sel.Sel.Name == "DUTDevice" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isStructType checks if the type is a struct
+func isStructType(expr ast.Expr) bool {
+	_, ok := expr.(*ast.StructType)
+	return ok
+}
+
+// isPointerToStruct checks if the type is a pointer to a struct
+func isPointerToStruct(expr ast.Expr) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		_, ok2 := star.X.(*ast.StructType)
+		return ok2
+	}
+	return false
+}
+
+// stringLiteralRE matches the first double-quoted string on a line.
+var stringLiteralRE = regexp.MustCompile(`"(.*?)"`)
+
+// Extract string literal from errors.New("...")
+func extractStringLiteral(line string) string {
+	matches := stringLiteralRE.FindStringSubmatch(line)
+	if len(matches) > 1 {
+		return matches[1]
+	}
+	return ""
+}
+
+// MixedCaps regex rules
+var (
+	exportedMixedCaps   = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	unexportedMixedCaps = regexp.MustCompile(`^[a-z][A-Za-z0-9]*$`)
+	snakeCase           = regexp.MustCompile(`_`)
+	badAcronyms         = regexp.MustCompile(`Id|Url|Http`) // common violations
+)
+
+func checkMixedCaps(path string, fset *token.FileSet, f *ast.File, errs *[]Issue) {
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			name := fn.Name.Name
+			kind := "function"
+			if fn.Recv != nil {
+				kind = "method"
+			}
+			pos := fset.Position(fn.Name.Pos())
+			if snakeCase.MatchString(name) {
+				report(errs, "mixed-caps", pos, "%s name %q should not use snake_case", kind, name)
+			}
+			if fn.Name.IsExported() {
+				if !exportedMixedCaps.MatchString(name) {
+					report(errs, "mixed-caps", pos, "exported %s name %q should use MixedCaps", kind, name)
+				}
+			} else {
+				if !unexportedMixedCaps.MatchString(name) {
+					report(errs, "mixed-caps", pos, "unexported %s name %q should use mixedCaps", kind, name)
+				}
+			}
+			if badAcronyms.MatchString(name) {
+				report(errs, "initialism", pos, "%s name %q has mis-cased acronym (use ID/URL/HTTP)", kind, name)
+			}
+		}
+		if gd, ok := decl.(*ast.GenDecl); ok {
+			for _, spec := range gd.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					name := ts.Name.Name
+					if snakeCase.MatchString(name) {
+						report(errs, "mixed-caps", fset.Position(ts.Pos()), "type name %q should not use snake_case", name)
+					}
+					if ts.Name.IsExported() {
+						if !exportedMixedCaps.MatchString(name) {
+							report(errs, "mixed-caps", fset.Position(ts.Pos()), "exported type name %q should use MixedCaps", name)
+						}
+					}
+					if badAcronyms.MatchString(name) {
+						report(errs, "initialism", fset.Position(ts.Pos()), "type name %q has mis-cased acronym", name)
+					}
+					switch t := ts.Type.(type) {
+					case *ast.StructType:
+						checkMemberMixedCaps(path, fset, "field", name, t.Fields, errs)
+					case *ast.InterfaceType:
+						checkMemberMixedCaps(path, fset, "interface method", name, t.Methods, errs)
+					}
+				}
+				if vs, ok := spec.(*ast.ValueSpec); ok {
+					for _, ident := range vs.Names {
+						name := ident.Name
+						if snakeCase.MatchString(name) {
+							report(errs, "mixed-caps", fset.Position(ident.Pos()), "variable name %q should not use snake_case", name)
+						}
+						if ident.IsExported() {
+							if !exportedMixedCaps.MatchString(name) {
+								report(errs, "mixed-caps", fset.Position(ident.Pos()), "exported var name %q should use MixedCaps", name)
+							}
+							if vs.Doc != nil {
+								docText := strings.TrimSpace(vs.Doc.Text())
+								if !strings.HasPrefix(docText, name) {
+									report(errs, "doc-comment", fset.Position(ident.Pos()), "doc comment for exported variable %q should start with the exact variable name (case-sensitive)", name)
+								}
+							}
+						}
+						if badAcronyms.MatchString(name) {
+							report(errs, "initialism", fset.Position(ident.Pos()), "variable name %q has mis-cased acronym", name)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// checkMemberMixedCaps applies the MixedCaps rules to the named members of
+// type typeName, i.e. struct fields or interface methods as given by kind.
+func checkMemberMixedCaps(path string, fset *token.FileSet, kind, typeName string, members *ast.FieldList, errs *[]Issue) {
+	if members == nil {
+		return
+	}
+	for _, member := range members.List {
+		for _, ident := range member.Names {
+			name := ident.Name
+			qualified := typeName + "." + name
+			pos := fset.Position(ident.Pos())
+			if name == "_" {
+				continue
+			}
+			if snakeCase.MatchString(name) {
+				report(errs, "mixed-caps", pos, "%s name %q should not use snake_case", kind, qualified)
+			}
+			if ident.IsExported() {
+				if !exportedMixedCaps.MatchString(name) {
+					report(errs, "mixed-caps", pos, "exported %s name %q should use MixedCaps", kind, qualified)
+				}
+			} else if !unexportedMixedCaps.MatchString(name) {
+				report(errs, "mixed-caps", pos, "unexported %s name %q should use mixedCaps", kind, qualified)
+			}
+			if badAcronyms.MatchString(name) {
+				report(errs, "initialism", pos, "%s name %q has mis-cased acronym (use ID/URL/HTTP)", kind, qualified)
+			}
+		}
+	}
+}
+
+// codeLikeCommentRE matches comment lines that read like Go code.
+var codeLikeCommentRE = regexp.MustCompile(
+	`^\s*//\s*(` +
+		// Control flow.
+		`if\b|else\b|switch\b|case\b|default\b|select\b|` +
+		`for\b|range\b|go\b|defer\b|` +
+		`return\b|break\b|continue\b|goto\b|fallthrough\b|` +
+
+		// Declarations.
+		`func\b|type\b|struct\b|interface\b|const\b|var\b|` +
+
+		// Built-ins.
+		`append\(|make\(|new\(|copy\(|delete\(|close\(|panic\(|recover\(|` +
+
+		// Assignment.
+		`[A-Za-z_][A-Za-z0-9_]*\s*:=|` +
+		`[A-Za-z_][A-Za-z0-9_]*\s*=|` +
+
+		// Generic method call.
+		`[A-Za-z_][A-Za-z0-9_]*\.[A-Za-z_][A-Za-z0-9_]*\(|` +
+
+		// Generic function call.
+		`[A-Za-z_][A-Za-z0-9_]*\(|` +
+
+		// Composite literal.
+		`&?[A-Za-z_][A-Za-z0-9_]*\{|` +
+
+		// nil.
+		`nil\b` +
+		`)`,
+)
+
+func (v *Validator) validateCommentedCode(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		v.forEachLine(path, errs, func(lineNo int, line string) {
+			if codeLikeCommentRE.MatchString(line) {
+				report(errs, "commented-code", token.Position{Filename: path, Line: lineNo}, "commented-out code detected: %s", strings.TrimSpace(line))
+			}
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+func validateUnusedParameters(root string, errs *[]Issue) error {
+	fset := token.NewFileSet()
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || fn.Type.Params == nil {
+				continue
+			}
+
+			// Collect parameter names.
+			params := map[string]token.Pos{}
+			for _, field := range fn.Type.Params.List {
+				for _, name := range field.Names {
+					if name.Name == "_" {
+						continue
+					}
+					params[name.Name] = name.Pos()
+				}
+			}
+
+			if len(params) == 0 {
+				continue
+			}
+
+			// Track parameter usage inside the function body.
+			used := make(map[string]bool)
+
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				id, ok := n.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				if _, ok := params[id.Name]; ok {
+					used[id.Name] = true
+				}
+				return true
+			})
+
+			for param := range params {
+				if !used[param] {
+					pos := fset.Position(params[param])
+					report(errs, "unused-param", pos, "parameter %q is declared but never used in function %q", param, fn.Name.Name)
+				}
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+func validateErrorsNewUsage(root string, errs *[]Issue) error {
+	fset := token.NewFileSet()
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			pkg, ok := sel.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+
+			if pkg.Name == "errors" && sel.Sel.Name == "New" {
+				pos := fset.Position(call.Pos())
+				report(errs, "errors-new", pos, "use fmt.Errorf instead of errors.New")
+			}
+
+			return true
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+func validateUnusedStructFields(root string, errs *[]Issue) error {
+	type fieldInfo struct {
+		File string
+		Line int
+		Name string
+	}
+
+	fields := make(map[string]fieldInfo)
+	used := make(map[string]bool)
+
+	fset := token.NewFileSet()
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		// Collect every struct field.
+		ast.Inspect(file, func(n ast.Node) bool {
+			ts, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return true
+			}
+
+			for _, f := range st.Fields.List {
+				for _, name := range f.Names {
+					pos := fset.Position(name.Pos())
+					key := ts.Name.Name + "." + name.Name
+
+					fields[key] = fieldInfo{
+						File: pos.Filename,
+						Line: pos.Line,
+						Name: key,
+					}
+				}
+			}
+			return true
+		})
+
+		// Mark fields initialized in composite literals.
+		ast.Inspect(file, func(n ast.Node) bool {
+			cl, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+
+			ident, ok := cl.Type.(*ast.Ident)
+			if !ok {
+				return true
+			}
+
+			for _, elt := range cl.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+
+				keyIdent, ok := kv.Key.(*ast.Ident)
+				if !ok {
+					continue
+				}
+
+				key := ident.Name + "." + keyIdent.Name
+				used[key] = true
+			}
+
+			return true
+		})
+
+		// Mark fields accessed using selectors.
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			for key := range fields {
+				if strings.HasSuffix(key, "."+sel.Sel.Name) {
+					used[key] = true
+				}
+			}
+
+			return true
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for key, f := range fields {
+		if !used[key] {
+			report(errs, "unused-field", token.Position{Filename: f.File, Line: f.Line}, "struct field %q is never used", key)
+		}
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+func validateHardcodedTimeout(root string, errs *[]Issue) error {
+	fset := token.NewFileSet()
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			for _, arg := range call.Args {
+				if isHardcodedDuration(arg) {
+					pos := fset.Position(arg.Pos())
+					report(errs, "hardcoded-timeout", pos, "hardcoded timeout detected, use a named constant instead")
+				}
+			}
+
+			return true
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+func isHardcodedDuration(expr ast.Expr) bool {
+	switch e := expr.(type) {
+
+	// 10*time.Minute
+	// 5*time.Second
+	// 2*time.Hour
+	case *ast.BinaryExpr:
+		if e.Op != token.MUL {
+			return false
+		}
+
+		_, leftIsNum := e.X.(*ast.BasicLit)
+
+		sel, rightIsSelector := e.Y.(*ast.SelectorExpr)
+		if !leftIsNum || !rightIsSelector {
+			return false
+		}
+
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok || pkg.Name != "time" {
+			return false
+		}
+
+		switch sel.Sel.Name {
+		case "Nanosecond",
+			"Microsecond",
+			"Millisecond",
+			"Second",
+			"Minute",
+			"Hour":
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateMixedGNMIBatchUsage(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+
+			var (
+				hasBatch     bool
+				hasImmediate bool
+			)
+
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+
+				pkg, ok := sel.X.(*ast.Ident)
+				if !ok {
+					return true
+				}
+
+				switch pkg.Name {
+
+				// gNMI APIs
+				case "gnmi":
+					switch sel.Sel.Name {
+
+					// Batched APIs
+					case "BatchUpdate",
+						"BatchReplace",
+						"BatchDelete":
+						hasBatch = true
+
+					// Immediate APIs
+					case "Update",
+						"Replace",
+						"Delete",
+						"Set":
+						hasImmediate = true
+					}
+
+				}
+
+				return true
+			})
+
+			if hasBatch && hasImmediate {
+				pos := fset.Position(fn.Pos())
+				suggest(errs, "gnmi-batch-mix", pos, "use a single SetBatch for consistency", "function %q mixes batched and immediate gNMI operations", fn.Name.Name)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+func validateHardcodedSubinterfaceIndex(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			var funcName string
+
+			switch fun := call.Fun.(type) {
+			case *ast.SelectorExpr:
+				funcName = fun.Sel.Name
+
+			case *ast.Ident:
+				funcName = fun.Name
+
+			default:
+				return true
+			}
+
+			switch funcName {
+			case "GetOrCreateSubinterface",
+				"Subinterface",
+				"NewOCSubInterface",
+				"AssignToNetworkInstance":
+
+				for _, arg := range call.Args {
+					lit, ok := arg.(*ast.BasicLit)
+					if !ok || lit.Kind != token.INT {
+						continue
+					}
+
+					pos := fset.Position(arg.Pos())
+
+					suggest(errs, "subinterface-index", pos, "use the subinterface ID from attrs instead", "hardcoded subinterface index %s passed to %s()", lit.Value, funcName)
+				}
+			}
+
+			return true
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+// validateOCListKeys flags fmt.Sprintf building the key of a generated OC
+// list, either as an argument of a list accessor (GetOrCreateX, GetX,
+// DeleteX or a gnmi.OC() path element) or as the index of a list map. Keys
+// formatted from several values stand in for a typed key struct and break
+// silently when the key format changes; pass the key fields to the typed
+// accessor instead.
+func validateOCListKeys(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok || !isOCListAccessor(sel) {
+				return true
+			}
+			for _, arg := range node.Args {
+				if isCompositeKeySprintf(arg) {
+					pos := fs.Position(arg.Pos())
+					suggest(errs, "oc-list-key", pos, "pass the key fields to the typed accessor instead", "list key for %s() built with fmt.Sprintf", sel.Sel.Name)
+				}
+			}
+		case *ast.IndexExpr:
+			if _, ok := node.X.(*ast.SelectorExpr); ok && isCompositeKeySprintf(node.Index) {
+				pos := fs.Position(node.Index.Pos())
+				suggest(errs, "oc-list-key", pos, "use the typed key struct or accessor instead", "list map %s indexed with a key built by fmt.Sprintf", types.ExprString(node.X))
+			}
+		}
+		return true
+	})
+}
+
+// isOCListAccessor reports whether sel names a generated list accessor or a
+// path element of a gnmi.OC() / ocpath.Root() chain.
+func isOCListAccessor(sel *ast.SelectorExpr) bool {
+	for _, prefix := range []string{"GetOrCreate", "Get", "Delete"} {
+		if strings.HasPrefix(sel.Sel.Name, prefix) && len(sel.Sel.Name) > len(prefix) {
+			return true
+		}
+	}
+
+	for x := sel.X; ; {
+		switch e := x.(type) {
+		case *ast.CallExpr:
+			if s, ok := e.Fun.(*ast.SelectorExpr); ok && (s.Sel.Name == "OC" || s.Sel.Name == "Root") {
+				return true
+			}
+			x = e.Fun
+		case *ast.SelectorExpr:
+			x = e.X
+		default:
+			return false
+		}
+	}
+}
+
+// isCompositeKeySprintf reports whether expr is a fmt.Sprintf call joining
+// several values into one string.
+func isCompositeKeySprintf(expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) < 3 {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Sprintf" {
+		return false
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "fmt" {
+		return false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return false
+	}
+	format, err := strconv.Unquote(lit.Value)
+	return err == nil && len(parseFormat(format)) >= 2
+}
+
+// validateMutexDefer requires mu.Lock() and mu.RLock() to be followed by
+// defer mu.Unlock() / defer mu.RUnlock() in the same block. Unlocking
+// explicitly is allowed only in the unlock-before-return pattern: the lock
+// is released before every return after it, and by the end of the block.
+func validateMutexDefer(path string, fs *token.FileSet, f *ast.File, typesInfo *types.Info, errs *[]Issue) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+		for i, stmt := range block.List {
+			mu, lock, ok := mutexCall(typesInfo, stmt, "Lock", "RLock")
+			if !ok {
+				continue
+			}
+			unlock := mutexUnlocks[lock]
+
+			if i+1 < len(block.List) {
+				if d, ok := block.List[i+1].(*ast.DeferStmt); ok {
+					if m, _, ok := mutexCall(typesInfo, &ast.ExprStmt{X: d.Call}, unlock); ok && m == mu {
+						continue
+					}
+				}
+			}
+			paths := unlockPaths{typesInfo: typesInfo, mu: mu, lock: lock, unlock: unlock}
+			if held := paths.walk(block.List[i+1:], true); !held && paths.unlocked > 0 && paths.locked == 0 {
+				continue
+			}
+
+			pos := fs.Position(stmt.Pos())
+			report(errs, "mutex-defer", pos, "%s.%s() is not followed by defer %s.%s(); manual unlock paths can leave the lock held", mu, lock, mu, unlock)
+		}
+		return true
+	})
+}
+
+// unlockPaths counts the returns after mu.lock() that run with mu unlocked
+// and those that run with it still held.
+type unlockPaths struct {
+	typesInfo        *types.Info
+	mu, lock, unlock string
+	unlocked, locked int
+}
+
+// walk follows stmts, which start with mu held or not, and reports whether
+// mu is held after them. Nested blocks inherit the state of their parent;
+// what they unlock is not carried back out.
+func (p *unlockPaths) walk(stmts []ast.Stmt, held bool) bool {
+	for _, stmt := range stmts {
+		if m, _, ok := mutexCall(p.typesInfo, stmt, p.unlock); ok && m == p.mu {
+			held = false
+			continue
+		}
+		if m, _, ok := mutexCall(p.typesInfo, stmt, p.lock); ok && m == p.mu {
+			held = true
+			continue
+		}
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				if held {
+					p.locked++
+				} else {
+					p.unlocked++
+				}
+			case *ast.BlockStmt:
+				p.walk(n.List, held)
+				return false
+			case *ast.CaseClause:
+				p.walk(n.Body, held)
+				return false
+			case *ast.CommClause:
+				p.walk(n.Body, held)
+				return false
+			}
+			return true
+		})
+	}
+	return held
+}
+
+// mutexUnlocks maps each mutex lock method to its unlock method.
+var mutexUnlocks = map[string]string{"Lock": "Unlock", "RLock": "RUnlock"}
+
+// mutexCall matches a statement calling one of the given sync.Mutex or
+// sync.RWMutex methods and returns the receiver and the method name.
+func mutexCall(typesInfo *types.Info, stmt ast.Stmt, methods ...string) (string, string, bool) {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return "", "", false
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return "", "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !containsString(methods, sel.Sel.Name) {
+		return "", "", false
+	}
+	// Without type information any Lock method is taken for a mutex.
+	if s, ok := typesInfo.Selections[sel]; ok {
+		if pkg := s.Obj().Pkg(); pkg == nil || pkg.Path() != "sync" {
+			return "", "", false
+		}
+	}
+	return types.ExprString(sel.X), sel.Sel.Name, true
+}
+
+// validateWaitGroups flags sync.WaitGroup misuse: Add called inside the
+// goroutine it tracks, Wait on a local WaitGroup that is never added to,
+// and a WaitGroup declared outside a loop that adds and waits on it in
+// every iteration.
+func validateWaitGroups(path string, fs *token.FileSet, f *ast.File, typesInfo *types.Info, errs *[]Issue) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		// Add inside the goroutine races with Wait.
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			g, ok := n.(*ast.GoStmt)
+			if !ok {
+				return true
+			}
+			lit, ok := g.Call.Fun.(*ast.FuncLit)
+			if !ok {
+				return true
+			}
+			ast.Inspect(lit.Body, func(n ast.Node) bool {
+				if sel, ok := waitGroupCall(typesInfo, n, "Add"); ok {
+					pos := fs.Position(sel.Pos())
+					suggest(errs, "waitgroup", pos, "call it before the go statement", "%s.Add() is called inside the goroutine it tracks", types.ExprString(sel.X))
+				}
+				return true
+			})
+			return true
+		})
+
+		// Local WaitGroups can be checked in full.
+		var loops []ast.Node
+		var names []string
+		decls := make(map[string]token.Pos)
+		calls := make(map[string][]*ast.SelectorExpr)
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.ForStmt, *ast.RangeStmt:
+				loops = append(loops, node)
+			case *ast.ValueSpec:
+				if isWaitGroupType(node.Type) || (len(node.Values) > 0 && isWaitGroupValue(node.Values[0])) {
+					for _, name := range node.Names {
+						names = append(names, name.Name)
+						decls[name.Name] = name.Pos()
+					}
+				}
+			case *ast.AssignStmt:
+				if node.Tok == token.DEFINE && len(node.Lhs) == len(node.Rhs) {
+					for i, rhs := range node.Rhs {
+						if ident, ok := node.Lhs[i].(*ast.Ident); ok && isWaitGroupValue(rhs) {
+							names = append(names, ident.Name)
+							decls[ident.Name] = ident.Pos()
+						}
+					}
+				}
+			}
+			if sel, ok := waitGroupCall(typesInfo, n, "Add", "Go", "Wait"); ok {
+				if ident, ok := sel.X.(*ast.Ident); ok {
+					calls[ident.Name] = append(calls[ident.Name], sel)
+				}
+			}
+			return true
+		})
+
+		for _, name := range names {
+			declPos := decls[name]
+			var adds []*ast.SelectorExpr
+			for _, sel := range calls[name] {
+				if sel.Sel.Name != "Wait" {
+					adds = append(adds, sel)
+				}
+			}
+			for _, sel := range calls[name] {
+				if sel.Sel.Name != "Wait" {
+					continue
+				}
+				pos := fs.Position(sel.Pos())
+				if len(adds) == 0 {
+					report(errs, "waitgroup", pos, "%s.Wait() has no matching %s.Add(); it returns without waiting for anything", name, name)
+					continue
+				}
+				loop := innermostNode(loops, sel.Pos())
+				if loop == nil || (declPos >= loop.Pos() && declPos < loop.End()) {
+					continue
+				}
+				for _, add := range adds {
+					if add.Pos() >= loop.Pos() && add.Pos() < loop.End() {
+						suggest(errs, "waitgroup", pos, "declare it inside the loop", "WaitGroup %s is reused across loop iterations", name)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+// waitGroupCall matches a call of one of the given sync.WaitGroup methods.
+// Without type information any such method call is taken for one.
+func waitGroupCall(typesInfo *types.Info, n ast.Node, methods ...string) (*ast.SelectorExpr, bool) {
+	call, ok := n.(*ast.CallExpr)
+	if !ok {
+		return nil, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !containsString(methods, sel.Sel.Name) {
+		return nil, false
+	}
+	if s, ok := typesInfo.Selections[sel]; ok {
+		recv := s.Recv()
+		if ptr, ok := recv.(*types.Pointer); ok {
+			recv = ptr.Elem()
+		}
+		named, ok := recv.(*types.Named)
+		if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != "sync" || named.Obj().Name() != "WaitGroup" {
+			return nil, false
+		}
+	} else if ident, ok := sel.X.(*ast.Ident); !ok || !declaredWaitGroup(ident) {
+		return nil, false
+	}
+	return sel, true
+}
+
+// declaredWaitGroup reports whether ident is declared as a sync.WaitGroup
+// variable or parameter, judging by syntax alone.
+func declaredWaitGroup(ident *ast.Ident) bool {
+	if ident.Obj == nil {
+		return false
+	}
+	switch decl := ident.Obj.Decl.(type) {
+	case *ast.ValueSpec:
+		return isWaitGroupType(decl.Type) || (len(decl.Values) > 0 && isWaitGroupValue(decl.Values[0]))
+	case *ast.AssignStmt:
+		for i, lhs := range decl.Lhs {
+			if l, ok := lhs.(*ast.Ident); ok && l.Name == ident.Name && i < len(decl.Rhs) {
+				return isWaitGroupValue(decl.Rhs[i])
+			}
+		}
+	case *ast.Field:
+		typ := decl.Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		return isWaitGroupType(typ)
+	}
+	return false
+}
+
+// isWaitGroupType reports whether expr spells sync.WaitGroup.
+func isWaitGroupType(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "WaitGroup" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "sync"
+}
+
+// isWaitGroupValue reports whether expr creates a new sync.WaitGroup, as in
+// sync.WaitGroup{}, &sync.WaitGroup{} or new(sync.WaitGroup).
+func isWaitGroupValue(expr ast.Expr) bool {
+	if u, ok := expr.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		expr = u.X
+	}
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		return isWaitGroupType(e.Type)
+	case *ast.CallExpr:
+		ident, ok := e.Fun.(*ast.Ident)
+		return ok && ident.Name == "new" && len(e.Args) == 1 && isWaitGroupType(e.Args[0])
+	}
+	return false
+}
+
+// innermostNode returns the innermost of nodes enclosing pos, or nil.
+func innermostNode(nodes []ast.Node, pos token.Pos) ast.Node {
+	var inner ast.Node
+	for _, n := range nodes {
+		if pos >= n.Pos() && pos < n.End() && (inner == nil || n.Pos() >= inner.Pos()) {
+			inner = n
+		}
+	}
+	return inner
+}
+
+// validateUnbufferedSends flags goroutines sending on an unbuffered channel
+// that the spawning function receives from in a select with other cases,
+// e.g. a timeout. Once another case wins nobody receives, and the goroutine
+// blocks forever on its send.
+func validateUnbufferedSends(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		unbuffered := make(map[string]bool)
+		var sends []*ast.SendStmt
+		selects := make(map[string]*ast.SelectStmt)
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.AssignStmt:
+				if len(node.Lhs) != len(node.Rhs) {
+					return true
+				}
+				for i, rhs := range node.Rhs {
+					if ident, ok := node.Lhs[i].(*ast.Ident); ok && isUnbufferedMake(rhs) {
+						unbuffered[ident.Name] = true
+					}
+				}
+			case *ast.ValueSpec:
+				for i, value := range node.Values {
+					if i < len(node.Names) && isUnbufferedMake(value) {
+						unbuffered[node.Names[i].Name] = true
+					}
+				}
+			case *ast.GoStmt:
+				if lit, ok := node.Call.Fun.(*ast.FuncLit); ok {
+					ast.Inspect(lit.Body, func(n ast.Node) bool {
+						if send, ok := n.(*ast.SendStmt); ok {
+							sends = append(sends, send)
+						}
+						return true
+					})
+				}
+			case *ast.SelectStmt:
+				if len(node.Body.List) < 2 {
+					return true
+				}
+				for _, stmt := range node.Body.List {
+					if ch := receivedChannel(stmt.(*ast.CommClause).Comm); ch != "" && selects[ch] == nil {
+						selects[ch] = node
+					}
+				}
+			}
+			return true
+		})
+
+		reported := make(map[string]bool)
+		for _, send := range sends {
+			ident, ok := send.Chan.(*ast.Ident)
+			if !ok || !unbuffered[ident.Name] || selects[ident.Name] == nil || reported[ident.Name] {
+				continue
+			}
+			reported[ident.Name] = true
+			pos := fs.Position(send.Pos())
+			sel := fs.Position(selects[ident.Name].Pos())
+			suggest(errs, "unbuffered-send", pos, "give the channel a buffer of 1", "goroutine sends on unbuffered channel %s, which the select at line %d may stop receiving from, and then leaks", ident.Name, sel.Line)
+		}
+	}
+}
+
+// isUnbufferedMake reports whether expr is make(chan T) or make(chan T, 0).
+func isUnbufferedMake(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return false
+	}
+	if ident, ok := call.Fun.(*ast.Ident); !ok || ident.Name != "make" {
+		return false
+	}
+	if _, ok := call.Args[0].(*ast.ChanType); !ok {
+		return false
+	}
+	if len(call.Args) == 1 {
+		return true
+	}
+	lit, ok := call.Args[1].(*ast.BasicLit)
+	return ok && lit.Value == "0"
+}
+
+// receivedChannel returns the name of the channel a select case receives
+// from, or "" when the case does not receive from a named channel.
+func receivedChannel(comm ast.Stmt) string {
+	var expr ast.Expr
+	switch stmt := comm.(type) {
+	case *ast.ExprStmt:
+		expr = stmt.X
+	case *ast.AssignStmt:
+		if len(stmt.Rhs) == 1 {
+			expr = stmt.Rhs[0]
+		}
+	}
+	recv, ok := expr.(*ast.UnaryExpr)
+	if !ok || recv.Op != token.ARROW {
+		return ""
+	}
+	if ident, ok := recv.X.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// validateErrorStringMatch flags errors told apart by their text:
+// err.Error() compared with a string or passed to strings.Contains and
+// friends. Messages change between releases and vendors; errors.Is,
+// errors.As or, for gRPC errors, status.Code() keep working.
+func validateErrorStringMatch(path string, fs *token.FileSet, f *ast.File, typesInfo *types.Info, errs *[]Issue) {
+	grpc := importsGRPC(f)
+	ast.Inspect(f, func(n ast.Node) bool {
+		var recv, match ast.Expr
+		switch node := n.(type) {
+		case *ast.BinaryExpr:
+			if node.Op != token.EQL && node.Op != token.NEQ {
+				return true
+			}
+			if recv = errorStringCall(typesInfo, node.X); recv != nil {
+				match = node.Y
+			} else if recv = errorStringCall(typesInfo, node.Y); recv != nil {
+				match = node.X
+			}
+		case *ast.SwitchStmt:
+			recv = errorStringCall(typesInfo, node.Tag)
+			if recv != nil && len(node.Body.List) > 0 {
+				if clause := node.Body.List[0].(*ast.CaseClause); len(clause.List) > 0 {
+					match = clause.List[0]
+				}
+			}
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok || len(node.Args) != 2 || !stringsMatchFuncs[sel.Sel.Name] {
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "strings" {
+				return true
+			}
+			if recv = errorStringCall(typesInfo, node.Args[0]); recv != nil {
+				// Any needle counts: a variable holds a message just as well.
+				text, _ := constantString(typesInfo, node.Args[1])
+				reportErrorStringMatch(errs, fs.Position(n.Pos()), recv, text, grpc)
+			}
+			return true
+		}
+		if recv == nil || match == nil {
+			return true
+		}
+
+		text, ok := constantString(typesInfo, match)
+		if !ok {
+			return true
+		}
+		reportErrorStringMatch(errs, fs.Position(n.Pos()), recv, text, grpc)
+		return true
+	})
+}
+
+// reportErrorStringMatch reports err.Error() matched against text, pointing
+// gRPC code at status.Code.
+func reportErrorStringMatch(errs *[]Issue, pos token.Position, recv ast.Expr, text string, grpc bool) {
+	name := types.ExprString(recv)
+	if grpc || strings.Contains(text, "rpc error") || strings.Contains(text, "code = ") {
+		suggest(errs, "error-string-match", pos, fmt.Sprintf("compare status.Code(%s) for gRPC errors, or use errors.Is/errors.As", name), "%s.Error() matched by its message", name)
+		return
+	}
+	suggest(errs, "error-string-match", pos, "use errors.Is or errors.As instead", "%s.Error() matched by its message", name)
+}
+
+// stringsMatchFuncs are the strings functions that test one string against
+// another.
+var stringsMatchFuncs = map[string]bool{
+	"Contains":  true,
+	"HasPrefix": true,
+	"HasSuffix": true,
+	"EqualFold": true,
+	"Index":     true,
+}
+
+// errorStringCall returns x when expr is a call x.Error() without
+// arguments, and nil otherwise. When the type of x is known it must be an
+// error.
+func errorStringCall(typesInfo *types.Info, expr ast.Expr) ast.Expr {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Error" {
+		return nil
+	}
+	if tv, ok := typesInfo.Types[sel.X]; ok && tv.Type != nil && tv.Type != types.Typ[types.Invalid] {
+		errType := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+		if !types.Implements(tv.Type, errType) {
+			return nil
+		}
+	}
+	return sel.X
+}
+
+// importsGRPC reports whether f imports a gRPC package.
+func importsGRPC(f *ast.File) bool {
+	for _, imp := range f.Imports {
+		if strings.HasPrefix(strings.Trim(imp.Path.Value, `"`), "google.golang.org/grpc") {
+			return true
+		}
+	}
+	return false
+}
+
+// grpcCodeNames maps the numeric gRPC status codes to their constants in
+// google.golang.org/grpc/codes.
+var grpcCodeNames = []string{
+	"OK", "Canceled", "Unknown", "InvalidArgument", "DeadlineExceeded",
+	"NotFound", "AlreadyExists", "PermissionDenied", "ResourceExhausted",
+	"FailedPrecondition", "Aborted", "OutOfRange", "Unimplemented",
+	"Internal", "Unavailable", "DataLoss", "Unauthenticated",
+}
+
+// validateGRPCStatusCodes flags gRPC error handling that bypasses the status
+// and codes packages: codes compared with raw numbers, errors compared with a
+// newly built status error, which never matches, and GRPCStatus() type
+// assertions, which miss wrapped errors unlike status.FromError.
+func validateGRPCStatusCodes(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	statusPkg := localImportName(f, "google.golang.org/grpc/status")
+	codesPkg := localImportName(f, "google.golang.org/grpc/codes")
+	if statusPkg == "" && codesPkg == "" {
+		return
+	}
+
+	// Variables holding a *status.Status, whose Code() method gives a code.
+	statuses := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok && len(assign.Rhs) == 1 && isPkgCall(assign.Rhs[0], statusPkg, "FromError", "Convert", "New", "FromContextError") {
+			if ident, ok := assign.Lhs[0].(*ast.Ident); ok {
+				statuses[ident.Name] = true
+			}
+		}
+		return true
+	})
+	isCode := func(expr ast.Expr) bool {
+		call, ok := ast.Unparen(expr).(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		if isPkgCall(call, statusPkg, "Code") {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Code" || len(call.Args) != 0 {
+			return false
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok {
+			return statuses[ident.Name]
+		}
+		return isPkgCall(sel.X, statusPkg, "Convert", "New", "FromContextError")
+	}
+	codeName := func(lit *ast.BasicLit) string {
+		name := "codes"
+		if codesPkg != "" {
+			name = codesPkg
+		}
+		if n, err := strconv.Atoi(lit.Value); err == nil && n >= 0 && n < len(grpcCodeNames) {
+			return name + "." + grpcCodeNames[n]
+		}
+		return "a " + name + " constant"
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.BinaryExpr:
+			if node.Op != token.EQL && node.Op != token.NEQ {
+				return true
+			}
+			for _, pair := range [][2]ast.Expr{{node.X, node.Y}, {node.Y, node.X}} {
+				if lit := intLiteral(pair[1]); lit != nil && isCode(pair[0]) {
+					suggest(errs, "grpc-status", fs.Position(lit.Pos()), fmt.Sprintf("use %s", codeName(lit)), "gRPC code compared with raw number %s", lit.Value)
+					break
+				}
+				if isPkgCall(pair[1], statusPkg, "Error", "Errorf") {
+					suggest(errs, "grpc-status", fs.Position(node.Pos()), fmt.Sprintf("compare %s.Code(err) with the expected code", statusPkg), "error compared with a newly built status error, which never matches")
+					break
+				}
+			}
+		case *ast.SwitchStmt:
+			if node.Tag == nil || !isCode(node.Tag) {
+				return true
+			}
+			for _, stmt := range node.Body.List {
+				for _, expr := range stmt.(*ast.CaseClause).List {
+					if lit := intLiteral(expr); lit != nil {
+						suggest(errs, "grpc-status", fs.Position(lit.Pos()), fmt.Sprintf("use %s", codeName(lit)), "gRPC code compared with raw number %s", lit.Value)
+					}
+				}
+			}
+		case *ast.CallExpr:
+			if len(node.Args) == 1 && isPkgCall(node, codesPkg, "Code") {
+				if lit := intLiteral(node.Args[0]); lit != nil {
+					suggest(errs, "grpc-status", fs.Position(node.Pos()), fmt.Sprintf("use %s", codeName(lit)), "gRPC code built from raw number %s", lit.Value)
+				}
+			}
+		case *ast.TypeAssertExpr:
+			iface, ok := node.Type.(*ast.InterfaceType)
+			if !ok {
+				return true
+			}
+			for _, m := range iface.Methods.List {
+				if len(m.Names) == 1 && m.Names[0].Name == "GRPCStatus" {
+					suggest(errs, "grpc-status", fs.Position(node.Pos()), "use status.FromError(err) or status.Code(err)", "GRPCStatus() type assertion misses wrapped errors")
+				}
+			}
+		}
+		return true
+	})
+}
+
+// localImportName returns the name under which f refers to importPath, or ""
+// when f does not import it.
+func localImportName(f *ast.File, importPath string) string {
+	for _, imp := range f.Imports {
+		if strings.Trim(imp.Path.Value, `"`) != importPath {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return defaultImportName(importPath)
+	}
+	return ""
+}
+
+// isPkgCall reports whether expr calls one of funcs from the package imported
+// as pkg.
+func isPkgCall(expr ast.Expr, pkg string, funcs ...string) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || pkg == "" {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	if ident, ok := sel.X.(*ast.Ident); !ok || ident.Name != pkg {
+		return false
+	}
+	return slices.Contains(funcs, sel.Sel.Name)
+}
+
+// intLiteral returns expr as an integer literal, or nil.
+func intLiteral(expr ast.Expr) *ast.BasicLit {
+	if lit, ok := ast.Unparen(expr).(*ast.BasicLit); ok && lit.Kind == token.INT {
+		return lit
+	}
+	return nil
+}
+
+// stringLiteral returns the value of expr if it is a string literal.
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := ast.Unparen(expr).(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+// validateNilMapWrites flags writes into a map variable declared with var
+// and no value, or a named map result, before anything assigns it. Writing
+// into such a nil map panics at run time.
+func validateNilMapWrites(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		// nilMaps holds the maps still nil at the current point of the walk,
+		// which follows the source order.
+		nilMaps := make(map[string]bool)
+		if fn.Type.Results != nil {
+			for _, field := range fn.Type.Results.List {
+				if _, ok := field.Type.(*ast.MapType); ok {
+					for _, name := range field.Names {
+						nilMaps[name.Name] = true
+					}
+				}
+			}
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.ValueSpec:
+				if _, ok := node.Type.(*ast.MapType); ok && len(node.Values) == 0 {
+					for _, name := range node.Names {
+						nilMaps[name.Name] = true
+					}
+				}
+			case *ast.AssignStmt:
+				// Check the writes before the right-hand side can initialize.
+				for _, lhs := range node.Lhs {
+					if index, ok := lhs.(*ast.IndexExpr); ok {
+						reportNilMapWrite(errs, fs, nilMaps, index)
+					}
+				}
+				for _, lhs := range node.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						delete(nilMaps, ident.Name)
+					}
+				}
+			case *ast.IncDecStmt:
+				if index, ok := node.X.(*ast.IndexExpr); ok {
+					reportNilMapWrite(errs, fs, nilMaps, index)
+				}
+			case *ast.UnaryExpr:
+				// The map may be initialized through the pointer.
+				if ident, ok := node.X.(*ast.Ident); ok && node.Op == token.AND {
+					delete(nilMaps, ident.Name)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// reportNilMapWrite reports a write through index when it targets a map in
+// nilMaps, once per map.
+func reportNilMapWrite(errs *[]Issue, fs *token.FileSet, nilMaps map[string]bool, index *ast.IndexExpr) {
+	ident, ok := index.X.(*ast.Ident)
+	if !ok || !nilMaps[ident.Name] {
+		return
+	}
+	delete(nilMaps, ident.Name)
+	suggest(errs, "nil-map", fs.Position(index.Pos()), "initialize it with make(...) or a literal first", "write into map %s, which is nil here and panics", ident.Name)
+}
+
+// validateTypedNilReturns flags functions returning a nil pointer of a
+// concrete type as an interface such as error: the interface holds a type,
+// so callers see it as non-nil. It covers (*T)(nil) and pointers declared
+// with var and no value that are neither assigned unconditionally nor
+// checked against nil before being returned.
+func validateTypedNilReturns(path string, fs *token.FileSet, f *ast.File, typesInfo *types.Info, errs *[]Issue) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || fn.Type.Results == nil {
+			continue
+		}
+
+		var results []ast.Expr
+		for _, field := range fn.Type.Results.List {
+			for range max(len(field.Names), 1) {
+				results = append(results, field.Type)
+			}
+		}
+
+		// nilPointers maps the pointers that may still be nil to their type.
+		nilPointers := make(map[string]ast.Expr)
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if spec, ok := n.(*ast.ValueSpec); ok && len(spec.Values) == 0 {
+				if _, ok := spec.Type.(*ast.StarExpr); ok {
+					for _, name := range spec.Names {
+						nilPointers[name.Name] = spec.Type
+					}
+				}
+			}
+			return true
+		})
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if cmp, ok := n.(*ast.BinaryExpr); ok && (cmp.Op == token.EQL || cmp.Op == token.NEQ) {
+				for _, side := range []ast.Expr{cmp.X, cmp.Y} {
+					if ident, ok := side.(*ast.Ident); ok {
+						delete(nilPointers, ident.Name)
+					}
+				}
+			}
+			return true
+		})
+		for _, stmt := range fn.Body.List {
+			if assign, ok := stmt.(*ast.AssignStmt); ok {
+				for _, lhs := range assign.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						delete(nilPointers, ident.Name)
+					}
+				}
+			}
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncLit:
+				// Its returns belong to another signature.
+				return false
+			case *ast.ReturnStmt:
+				if len(node.Results) != len(results) {
+					return true
+				}
+				for i, res := range node.Results {
+					if !isInterfaceType(typesInfo, results[i]) {
+						continue
+					}
+					if ident, ok := res.(*ast.Ident); ok && nilPointers[ident.Name] != nil {
+						suggest(errs, "typed-nil", fs.Position(res.Pos()), "return a literal nil on that path", "%s may be a nil %s returned as %s, which callers see as non-nil", ident.Name, types.ExprString(nilPointers[ident.Name]), types.ExprString(results[i]))
+					} else if isTypedNilConversion(res) {
+						suggest(errs, "typed-nil", fs.Position(res.Pos()), "return a literal nil", "%s returned as %s is not nil for callers", types.ExprString(res), types.ExprString(results[i]))
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// isInterfaceType reports whether the type expression expr denotes an
+// interface. Without type information only error, any and interface
+// literals are recognized.
+func isInterfaceType(typesInfo *types.Info, expr ast.Expr) bool {
+	if tv, ok := typesInfo.Types[expr]; ok && tv.Type != nil && tv.Type != types.Typ[types.Invalid] {
+		return types.IsInterface(tv.Type)
+	}
+	switch e := expr.(type) {
+	case *ast.InterfaceType:
+		return true
+	case *ast.Ident:
+		return e.Name == "error" || e.Name == "any"
+	}
+	return false
+}
+
+// isTypedNilConversion reports whether expr is a conversion of nil to a
+// pointer type, e.g. (*T)(nil).
+func isTypedNilConversion(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return false
+	}
+	if _, ok := ast.Unparen(call.Fun).(*ast.StarExpr); !ok {
+		return false
+	}
+	ident, ok := call.Args[0].(*ast.Ident)
+	return ok && ident.Name == "nil"
+}
+
+// validateLoopCapture flags goroutines, and subtests calling t.Parallel(),
+// whose closures use a loop variable without a per-iteration copy. Before Go
+// 1.22 all iterations share the variable, so the closures typically all see
+// the last value and a table-driven test runs its last case N times.
+func (v *Validator) validateLoopCapture(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	goVersion := moduleGoVersion(filepath.Dir(path))
+	if !v.cfg.LoopCapture.Always && (goVersion == "" || version.Compare(goVersion, "go1.22") >= 0) {
+		return
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		var vars []*ast.Ident
+		var body *ast.BlockStmt
+		switch loop := n.(type) {
+		case *ast.RangeStmt:
+			if loop.Tok != token.DEFINE {
+				return true
+			}
+			for _, expr := range []ast.Expr{loop.Key, loop.Value} {
+				if ident, ok := expr.(*ast.Ident); ok && ident.Name != "_" {
+					vars = append(vars, ident)
+				}
+			}
+			body = loop.Body
+		case *ast.ForStmt:
+			init, ok := loop.Init.(*ast.AssignStmt)
+			if !ok || init.Tok != token.DEFINE {
+				return true
+			}
+			for _, expr := range init.Lhs {
+				if ident, ok := expr.(*ast.Ident); ok && ident.Name != "_" {
+					vars = append(vars, ident)
+				}
+			}
+			body = loop.Body
+		default:
+			return true
+		}
+
+		// A copy such as tc := tc anywhere in the body counts for the whole
+		// loop.
+		loopVars := make(map[string]bool)
+		for _, ident := range vars {
+			loopVars[ident.Name] = true
+		}
+		ast.Inspect(body, func(n ast.Node) bool {
+			if assign, ok := n.(*ast.AssignStmt); ok && assign.Tok == token.DEFINE {
+				for _, lhs := range assign.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						delete(loopVars, ident.Name)
+					}
+				}
+			}
+			return true
+		})
+		if len(loopVars) == 0 {
+			return true
+		}
+
+		ast.Inspect(body, func(n ast.Node) bool {
+			var lit *ast.FuncLit
+			var what string
+			switch node := n.(type) {
+			case *ast.GoStmt:
+				lit, _ = node.Call.Fun.(*ast.FuncLit)
+				what = "goroutine"
+			case *ast.CallExpr:
+				sel, ok := node.Fun.(*ast.SelectorExpr)
+				if !ok || sel.Sel.Name != "Run" || len(node.Args) != 2 {
+					return true
+				}
+				lit, _ = node.Args[1].(*ast.FuncLit)
+				if lit == nil || !callsParallel(lit) {
+					return true
+				}
+				what = "parallel subtest"
+			}
+			if lit == nil {
+				return true
+			}
+			if name := capturedLoopVar(lit, loopVars); name != "" {
+				suggest(errs, "loop-capture", fs.Position(lit.Pos()), fmt.Sprintf("copy it first (%s := %s) or pass it as an argument", name, name), "%s captures loop variable %s, which all iterations share before Go 1.22", what, name)
+			}
+			return true
+		})
+		return true
+	})
+}
+
+// callsParallel reports whether the body of lit calls X.Parallel().
+func callsParallel(lit *ast.FuncLit) bool {
+	found := false
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Parallel" && len(call.Args) == 0 {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// capturedLoopVar returns the first of loopVars that lit refers to without
+// declaring it as a parameter, or "".
+func capturedLoopVar(lit *ast.FuncLit, loopVars map[string]bool) string {
+	params := make(map[string]bool)
+	for _, field := range lit.Type.Params.List {
+		for _, name := range field.Names {
+			params[name.Name] = true
+		}
+	}
+
+	captured := ""
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			// Only the operand can name a variable.
+			ast.Inspect(node.X, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok && captured == "" && loopVars[ident.Name] && !params[ident.Name] {
+					captured = ident.Name
+				}
+				return captured == ""
+			})
+			return false
+		case *ast.Ident:
+			if captured == "" && loopVars[node.Name] && !params[node.Name] {
+				captured = node.Name
+			}
+		}
+		return captured == ""
+	})
+	return captured
+}
+
+// validateAppendUsage flags append calls that are likely copy-paste slips:
+// append(x) without elements, which does nothing, and y = append(x, ...),
+// which leaves x unchanged and may share its backing array with y. Appending
+// onto a new slice, e.g. append([]T(nil), x...) or append(x[:n:n], ...), and
+// declaring the result with := count as intentional.
+func validateAppendUsage(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if isAppendCall(node) && len(node.Args) == 1 && !node.Ellipsis.IsValid() {
+				suggest(errs, "append-misuse", fs.Position(node.Pos()), "add the missing values or drop the call", "append(%s) adds no elements", types.ExprString(node.Args[0]))
+			}
+		case *ast.AssignStmt:
+			if node.Tok != token.ASSIGN || len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, rhs := range node.Rhs {
+				call, ok := rhs.(*ast.CallExpr)
+				if !ok || !isAppendCall(call) || len(call.Args) < 2 {
+					continue
+				}
+				switch call.Args[0].(type) {
+				case *ast.Ident, *ast.SelectorExpr:
+				default:
+					// A literal, conversion, call or slicing yields a new slice.
+					continue
+				}
+				lhs, base := types.ExprString(node.Lhs[i]), types.ExprString(call.Args[0])
+				if lhs != base && lhs != "_" && base != "nil" {
+					suggest(errs, "append-misuse", fs.Position(call.Pos()), fmt.Sprintf("append to %s itself, or copy %s first if both are needed", lhs, base), "result of append to %s is assigned to %s", base, lhs)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// isAppendCall reports whether call calls the builtin append.
+func isAppendCall(call *ast.CallExpr) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	return ok && ident.Name == "append" && len(call.Args) > 0
+}
+
+// validateSlicePrealloc flags slices declared empty and then grown by one
+// append per iteration of a range loop over a slice, array, map or integer
+// in the same block. The final length is known up front, so
+// make([]T, 0, n) saves the repeated reallocations.
+func validateSlicePrealloc(path string, fs *token.FileSet, f *ast.File, typesInfo *types.Info, errs *[]Issue) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+
+		// empty maps the slices declared empty so far to their type.
+		empty := make(map[string]ast.Expr)
+		for _, stmt := range block.List {
+			for name, typ := range emptySliceDecls(stmt) {
+				empty[name] = typ
+			}
+			loop, ok := stmt.(*ast.RangeStmt)
+			if !ok || len(empty) == 0 || !hasKnownLength(typesInfo, loop.X) || hasBranch(loop.Body) {
+				continue
+			}
+
+			for _, s := range loop.Body.List {
+				name := selfAppend(s)
+				typ, ok := empty[name]
+				if !ok {
+					continue
+				}
+				size := "len(" + types.ExprString(loop.X) + ")"
+				if tv, ok := typesInfo.Types[loop.X]; ok && tv.Type != nil {
+					if basic, ok := tv.Type.Underlying().(*types.Basic); ok && basic.Info()&types.IsInteger != 0 {
+						size = types.ExprString(loop.X)
+					}
+				}
+				suggest(errs, "slice-prealloc", fs.Position(s.Pos()), fmt.Sprintf("allocate it with make(%s, 0, %s)", types.ExprString(typ), size), "%s grows one append per iteration over %s", name, types.ExprString(loop.X))
+				delete(empty, name)
+			}
+		}
+		return true
+	})
+}
+
+// emptySliceDecls returns the slices stmt declares with zero capacity,
+// var s []T, s := []T{} or s := make([]T, 0), mapped to their type.
+func emptySliceDecls(stmt ast.Stmt) map[string]ast.Expr {
+	decls := make(map[string]ast.Expr)
+	switch stmt := stmt.(type) {
+	case *ast.DeclStmt:
+		gen, ok := stmt.Decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			break
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if arr, ok := vs.Type.(*ast.ArrayType); ok && arr.Len == nil && len(vs.Values) == 0 {
+				for _, name := range vs.Names {
+					decls[name.Name] = vs.Type
+				}
+			}
+		}
+	case *ast.AssignStmt:
+		if stmt.Tok != token.DEFINE || len(stmt.Lhs) != len(stmt.Rhs) {
+			break
+		}
+		for i, rhs := range stmt.Rhs {
+			ident, ok := stmt.Lhs[i].(*ast.Ident)
+			if !ok {
+				continue
+			}
+			switch rhs := rhs.(type) {
+			case *ast.CompositeLit:
+				if arr, ok := rhs.Type.(*ast.ArrayType); ok && arr.Len == nil && len(rhs.Elts) == 0 {
+					decls[ident.Name] = rhs.Type
+				}
+			case *ast.CallExpr:
+				fn, ok := rhs.Fun.(*ast.Ident)
+				if !ok || fn.Name != "make" || len(rhs.Args) != 2 {
+					continue
+				}
+				if lit, ok := rhs.Args[1].(*ast.BasicLit); ok && lit.Value == "0" {
+					decls[ident.Name] = rhs.Args[0]
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// hasKnownLength reports whether ranging over x runs a number of iterations
+// known before the loop. Without type information only plain variables and
+// fields are trusted.
+func hasKnownLength(typesInfo *types.Info, x ast.Expr) bool {
+	if tv, ok := typesInfo.Types[x]; ok && tv.Type != nil && tv.Type != types.Typ[types.Invalid] {
+		switch t := tv.Type.Underlying().(type) {
+		case *types.Slice, *types.Array, *types.Map:
+			return true
+		case *types.Pointer:
+			_, ok := t.Elem().Underlying().(*types.Array)
+			return ok
+		case *types.Basic:
+			return t.Info()&types.IsInteger != 0
+		}
+		return false
+	}
+	switch x.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		return true
+	}
+	return false
+}
+
+// hasBranch reports whether body contains a break, continue, goto or return,
+// any of which can make the loop append fewer elements.
+func hasBranch(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BranchStmt, *ast.ReturnStmt:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// selfAppend returns s when stmt is s = append(s, elem), and "" otherwise.
+func selfAppend(stmt ast.Stmt) string {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return ""
+	}
+	ident, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok || !isAppendCall(call) || len(call.Args) != 2 || call.Ellipsis.IsValid() {
+		return ""
+	}
+	if base, ok := call.Args[0].(*ast.Ident); !ok || base.Name != ident.Name {
+		return ""
+	}
+	return ident.Name
+}
+
+// regexpCompileFuncs are the regexp functions compiling a pattern.
+var regexpCompileFuncs = []string{"Compile", "MustCompile", "CompilePOSIX", "MustCompilePOSIX"}
+
+// validateRegexpHotPaths flags regexps compiled from a constant pattern
+// inside a loop, inside a t.Run subtest, or inside a function of the same
+// file called from either. Each call compiles the pattern again; a
+// package-level var compiles it once.
+func validateRegexpHotPaths(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	regexpPkg := localImportName(f, "regexp")
+	if regexpPkg == "" {
+		return
+	}
+
+	// hot holds the loop and subtest bodies; hotFuncs the functions they
+	// call by name.
+	var hot []ast.Node
+	hotFuncs := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ForStmt:
+			hot = append(hot, node.Body)
+		case *ast.RangeStmt:
+			hot = append(hot, node.Body)
+		case *ast.CallExpr:
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Run" && len(node.Args) == 2 {
+				if lit, ok := node.Args[1].(*ast.FuncLit); ok {
+					hot = append(hot, lit.Body)
+				}
+			}
+		}
+		return true
+	})
+	for _, body := range hot {
+		ast.Inspect(body, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				if ident, ok := call.Fun.(*ast.Ident); ok {
+					hotFuncs[ident.Name] = true
+				}
+			}
+			return true
+		})
+	}
+
+	reported := make(map[token.Pos]bool)
+	check := func(n ast.Node, where string) {
+		ast.Inspect(n, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 || !isPkgCall(call, regexpPkg, regexpCompileFuncs...) || reported[call.Pos()] {
+				return true
+			}
+			if lit, ok := call.Args[0].(*ast.BasicLit); !ok || lit.Kind != token.STRING {
+				// Patterns built at run time cannot move to a package var.
+				return true
+			}
+			reported[call.Pos()] = true
+			suggest(errs, "regexp-hot-path", fs.Position(call.Pos()), "compile it once into a package-level var", "regexp compiled %s", where)
+			return true
+		})
+	}
+	for _, body := range hot {
+		check(body, "on every loop iteration or subtest")
+	}
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Body != nil && fn.Recv == nil && hotFuncs[fn.Name.Name] {
+			check(fn.Body, "on every call of "+fn.Name.Name+", which runs inside a loop or subtest")
+		}
+	}
+}
+
+// defaultLargeCopyThreshold is the size in bytes above which the large-copy
+// rule flags a copied value unless the config sets another.
+const defaultLargeCopyThreshold = 128
+
+// copySizes computes value sizes as the gc compiler does on 64-bit targets.
+var copySizes = types.SizesFor("gc", "amd64")
+
+// validateLargeCopies flags parameters, receivers and range values that copy
+// a struct or array larger than the configured threshold, such as a
+// generated OC struct. Types that cannot be resolved are skipped.
+func (v *Validator) validateLargeCopies(path string, fs *token.FileSet, f *ast.File, typesInfo *types.Info, errs *[]Issue) {
+	threshold := v.cfg.LargeCopy.Threshold
+	if threshold <= 0 {
+		threshold = defaultLargeCopyThreshold
+	}
+
+	largeType := func(ident *ast.Ident) (types.Type, int64) {
+		obj := typesInfo.Defs[ident]
+		if obj == nil {
+			return nil, 0
+		}
+		t := obj.Type()
+		switch t.Underlying().(type) {
+		case *types.Struct, *types.Array:
+		default:
+			return nil, 0
+		}
+		if size := copySizes.Sizeof(t); size > threshold {
+			return t, size
+		}
+		return nil, 0
+	}
+	// Types of the file's own package print unqualified.
+	qualifier := func(p *types.Package) string {
+		if p.Path() == f.Name.Name {
+			return ""
+		}
+		return p.Name()
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			var fields []*ast.Field
+			if node.Recv != nil {
+				fields = append(fields, node.Recv.List...)
+			}
+			fields = append(fields, node.Type.Params.List...)
+			for _, field := range fields {
+				for _, name := range field.Names {
+					t, size := largeType(name)
+					if t == nil {
+						continue
+					}
+					if node.Recv != nil && field == node.Recv.List[0] {
+						suggest(errs, "large-copy", fs.Position(name.Pos()), "use a pointer receiver instead", "receiver %s copies %d bytes of %s on every call of %s", name.Name, size, types.TypeString(t, qualifier), node.Name.Name)
+						continue
+					}
+					suggest(errs, "large-copy", fs.Position(name.Pos()), "pass a pointer instead", "parameter %s copies %d bytes of %s on every call of %s", name.Name, size, types.TypeString(t, qualifier), node.Name.Name)
+				}
+			}
+		case *ast.RangeStmt:
+			ident, ok := node.Value.(*ast.Ident)
+			if !ok || node.Tok != token.DEFINE || ident.Name == "_" {
+				return true
+			}
+			if t, size := largeType(ident); t != nil {
+				suggest(errs, "large-copy", fs.Position(ident.Pos()), "range over the index and use a pointer to the element instead", "range copies each %d-byte %s of %s into %s", size, types.TypeString(t, qualifier), types.ExprString(node.X), ident.Name)
+			}
+		}
+		return true
+	})
+}
+
+// validateContextFields flags struct types storing a context.Context. A
+// context belongs to one call chain; kept in a struct it outlives its
+// cancellation and hides which calls it governs. Pass ctx as the first
+// parameter instead.
+func validateContextFields(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	contextPkg := localImportName(f, "context")
+	if contextPkg == "" {
+		return
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		for _, field := range st.Fields.List {
+			sel, ok := field.Type.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Context" {
+				continue
+			}
+			if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != contextPkg {
+				continue
+			}
+			name := "an embedded field"
+			if len(field.Names) > 0 {
+				name = "field " + field.Names[0].Name
+			}
+			suggest(errs, "context-field", fs.Position(field.Pos()), "pass ctx as the first parameter of the methods that need it instead", "struct %s stores a context.Context in %s", spec.Name.Name, name)
+		}
+		return true
+	})
+}
+
+// contextCancelFuncs are the context constructors returning a cancel func.
+var contextCancelFuncs = []string{"WithCancel", "WithCancelCause", "WithTimeout", "WithTimeoutCause", "WithDeadline", "WithDeadlineCause"}
+
+// validateContextCancel flags context.WithCancel/WithTimeout/WithDeadline
+// results whose cancel func is discarded or never used. Until it is called
+// the context and its timer stay alive, piling up over long test runs.
+func validateContextCancel(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	contextPkg := localImportName(f, "context")
+	if contextPkg == "" {
+		return
+	}
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		// uses counts the references to each name in the function,
+		// closures included.
+		uses := make(map[string]int)
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok {
+				uses[ident.Name]++
+			}
+			return true
+		})
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok || len(assign.Lhs) != 2 || len(assign.Rhs) != 1 || !isPkgCall(assign.Rhs[0], contextPkg, contextCancelFuncs...) {
+				return true
+			}
+			call := assign.Rhs[0].(*ast.CallExpr).Fun.(*ast.SelectorExpr)
+			cancel, ok := assign.Lhs[1].(*ast.Ident)
+			if !ok {
+				return true
+			}
+			pos := fs.Position(cancel.Pos())
+			switch {
+			case cancel.Name == "_":
+				suggest(errs, "context-cancel", pos, "keep it and defer cancel() so the context is released", "cancel func of %s.%s is discarded", contextPkg, call.Sel.Name)
+			case uses[cancel.Name] == 1:
+				suggest(errs, "context-cancel", pos, fmt.Sprintf("defer %s() right after creating the context", cancel.Name), "cancel func %s of %s.%s is never called", cancel.Name, contextPkg, call.Sel.Name)
+			}
+			return true
+		})
+	}
+}
+
+// validateOTGFlowNames checks the names of OTG traffic flows: each flow is
+// named once, with a constant, and the flow metrics read through
+// gnmi.OTG().Flow(name) name a flow the file configures. A mistyped name
+// there returns zero counters, which loss checks read as a pass.
+func validateOTGFlowNames(path string, fs *token.FileSet, f *ast.File, typesInfo *types.Info, errs *[]Issue) {
+	// Variables holding flows created by Flows().Add().
+	flowVars := make(map[types.Object]bool)
+	isFlowsAdd := func(expr ast.Expr) bool {
+		call, ok := ast.Unparen(expr).(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Add" {
+			return false
+		}
+		inner, ok := sel.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		flows, ok := inner.Fun.(*ast.SelectorExpr)
+		return ok && flows.Sel.Name == "Flows"
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok && len(assign.Lhs) == len(assign.Rhs) {
+			for i, rhs := range assign.Rhs {
+				if ident, ok := assign.Lhs[i].(*ast.Ident); ok && isFlowsAdd(rhs) {
+					if obj := typesInfo.ObjectOf(ident); obj != nil {
+						flowVars[obj] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	named := make(map[string]token.Position)
+	allConstant := true
+	type metricRead struct {
+		name string
+		pos  token.Position
+	}
+	var reads []metricRead
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		switch sel.Sel.Name {
+		case "SetName":
+			flow := isFlowsAdd(sel.X)
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				flow = flowVars[typesInfo.ObjectOf(ident)]
+			}
+			if !flow {
+				return true
+			}
+			pos := fs.Position(call.Pos())
+			if lit, ok := ast.Unparen(call.Args[0]).(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				suggest(errs, "otg-flow-name", pos, "declare the name as a constant and read the flow metrics with the same constant", "flow named with literal %s", lit.Value)
+			}
+			name, ok := constantString(typesInfo, call.Args[0])
+			if !ok {
+				allConstant = false
+				return true
+			}
+			if first, dup := named[name]; dup {
+				report(errs, "otg-flow-name", pos, "flow %q is already defined at line %d; flow names must be unique for their metrics to be told apart", name, first.Line)
+			} else {
+				named[name] = pos
+			}
+		case "Flow":
+			otg, ok := sel.X.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if otgSel, ok := otg.Fun.(*ast.SelectorExpr); !ok || otgSel.Sel.Name != "OTG" {
+				return true
+			}
+			if name, ok := constantString(typesInfo, call.Args[0]); ok {
+				reads = append(reads, metricRead{name, fs.Position(call.Pos())})
+			}
+		}
+		return true
+	})
+
+	// Only a file configuring all its flows with known names can tell a
+	// mistyped metric name from a flow defined elsewhere.
+	if len(named) == 0 || !allConstant {
+		return
+	}
+	for _, read := range reads {
+		if _, ok := named[read.name]; !ok {
+			report(errs, "otg-flow-name", read.pos, "flow metrics read for %q, but no flow of that name is configured; the counters will read zero", read.name)
+		}
+	}
+}
+
+// gribiFluentPath is the import path of the gRIBI fluent client.
+const gribiFluentPath = "github.com/openconfig/gribigo/fluent"
+
+// validateGRIBIClients checks gRIBI fluent clients against the test
+// guidance: a connection configured in the function that created the client
+// sets its election ID and persistence explicitly instead of relying on
+// server defaults, and results are awaited with a timeout so a stuck DUT
+// fails the test instead of hanging it.
+func validateGRIBIClients(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	fluent := localImportName(f, gribiFluentPath)
+	if fluent == "" {
+		return
+	}
+
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		// Clients created here, and the contexts that carry a deadline.
+		clients := make(map[string]bool)
+		deadlines := make(map[string]bool)
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok || len(assign.Rhs) != 1 {
+				return true
+			}
+			ident, ok := assign.Lhs[0].(*ast.Ident)
+			if !ok {
+				return true
+			}
+			switch {
+			case isPkgCall(assign.Rhs[0], fluent, "NewClient"):
+				clients[ident.Name] = true
+			case isPkgCall(assign.Rhs[0], localImportName(f, "context"), "WithTimeout", "WithDeadline"):
+				deadlines[ident.Name] = true
+			}
+			return true
+		})
+		if len(clients) == 0 {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.ExprStmt:
+				client, options := connectionChain(node.X)
+				if !clients[client] {
+					return true
+				}
+				pos := fs.Position(node.Pos())
+				allPrimary := false
+				if mode, ok := options["WithRedundancyMode"]; ok && len(mode.Args) == 1 {
+					allPrimary = strings.HasSuffix(types.ExprString(mode.Args[0]), "AllPrimaryClients")
+				}
+				if _, ok := options["WithInitialElectionID"]; !ok && !allPrimary {
+					suggest(errs, "gribi-client", pos, "add WithInitialElectionID so the client is elected primary deterministically", "connection of gRIBI client %s sets no election ID", client)
+				}
+				if _, ok := options["WithPersistence"]; !ok {
+					suggest(errs, "gribi-client", pos, "add WithPersistence so entries survive the client disconnecting", "connection of gRIBI client %s does not set persistence", client)
+				}
+			case *ast.CallExpr:
+				sel, ok := node.Fun.(*ast.SelectorExpr)
+				if !ok || sel.Sel.Name != "Await" || len(node.Args) == 0 {
+					return true
+				}
+				if ident, ok := sel.X.(*ast.Ident); !ok || !clients[ident.Name] {
+					return true
+				}
+				if ctx, ok := node.Args[0].(*ast.Ident); ok && deadlines[ctx.Name] {
+					return true
+				}
+				suggest(errs, "gribi-client", fs.Position(node.Pos()), "use AwaitTimeout or a context with a deadline", "%s.Await waits without a timeout and hangs the test if the DUT never answers", types.ExprString(sel.X))
+			}
+			return true
+		})
+	}
+}
+
+// connectionChain unwinds a chain such as
+// c.Connection().WithStub(s).WithPersistence() into the client it starts
+// from and its option calls by name. client is "" when expr is no such
+// chain.
+func connectionChain(expr ast.Expr) (client string, options map[string]*ast.CallExpr) {
+	options = make(map[string]*ast.CallExpr)
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return "", nil
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return "", nil
+		}
+		if sel.Sel.Name == "Connection" {
+			ident, ok := sel.X.(*ast.Ident)
+			if !ok {
+				return "", nil
+			}
+			return ident.Name, options
+		}
+		options[sel.Sel.Name] = call
+		expr = sel.X
+	}
+}
+
+// gnoiOperations maps the disruptive gNOI RPCs to the service accessor their
+// client is reached through, e.g. gnoiClient.System().Reboot(...).
+var gnoiOperations = map[string]string{
+	"Reboot":      "System",
+	"KillProcess": "System",
+	"Remove":      "File",
+}
+
+// defaultGNOIHealthChecks are the gnmi calls that re-verify the DUT after a
+// disruptive operation.
+var defaultGNOIHealthChecks = []string{"Await", "Get", "GetAll", "Lookup", "Watch"}
+
+// validateGNOISafety flags gNOI reboots, process kills and file removals that
+// are neither inside an if guarded by a configured testbed check nor followed
+// in the same function by a call re-verifying the DUT, such as a gnmi.Get of
+// its boot time. Unchecked reboots have left lab devices down mid-suite.
+func (v *Validator) validateGNOISafety(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	usesGNOI := slices.ContainsFunc(f.Imports, func(imp *ast.ImportSpec) bool {
+		return strings.HasPrefix(strings.Trim(imp.Path.Value, `"`), "github.com/openconfig/gnoi/")
+	})
+	if !usesGNOI {
+		return
+	}
+	cfg := v.cfg.GNOISafety
+	gnmi := localImportName(f, "github.com/openconfig/ondatra/gnmi")
+
+	// healthCheck reports whether call re-verifies the DUT.
+	healthCheck := func(call *ast.CallExpr) bool {
+		if isPkgCall(call, gnmi, defaultGNOIHealthChecks...) {
+			return true
+		}
+		name := types.ExprString(call.Fun)
+		return slices.ContainsFunc(cfg.HealthChecks, func(check string) bool {
+			return name == check || strings.HasSuffix(name, "."+check)
+		})
+	}
+	// guarded reports whether cond mentions one of the configured guards.
+	guarded := func(cond ast.Expr) bool {
+		found := false
+		ast.Inspect(cond, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && slices.Contains(cfg.Guards, ident.Name) {
+				found = true
+			}
+			return !found
+		})
+		return found
+	}
+
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		var ops []*ast.CallExpr
+		var checks []token.Pos
+		var stack []ast.Node
+		guardedOps := make(map[*ast.CallExpr]bool)
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if n == nil {
+				stack = stack[:len(stack)-1]
+				return true
+			}
+			stack = append(stack, n)
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if healthCheck(call) {
+				checks = append(checks, call.Pos())
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			service, ok := gnoiOperations[sel.Sel.Name]
+			if !ok {
+				return true
+			}
+			accessor, ok := sel.X.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if as, ok := accessor.Fun.(*ast.SelectorExpr); !ok || as.Sel.Name != service {
+				return true
+			}
+			ops = append(ops, call)
+			for _, outer := range stack {
+				if ifStmt, ok := outer.(*ast.IfStmt); ok && guarded(ifStmt.Cond) {
+					guardedOps[call] = true
+				}
+			}
+			return true
+		})
+
+		for _, op := range ops {
+			if guardedOps[op] || slices.ContainsFunc(checks, func(pos token.Pos) bool { return pos > op.End() }) {
+				continue
+			}
+			name := op.Fun.(*ast.SelectorExpr).Sel.Name
+			suggest(errs, "gnoi-safety", fs.Position(op.Pos()), "verify it, e.g. gnmi.Get of the system boot time, or guard the call with a testbed check", "gNOI %s is not followed by a check that the DUT is healthy again", name)
+		}
+	}
+}
+
+// validatePortAssumptions flags tests tied to one lab topology: ports picked
+// by their position in dut.Ports(), an exact port count, and port speeds
+// hardcoded instead of read from the testbed. Ports are looked up by their
+// testbed ID, e.g. dut.Port(t, "port1"), so the test runs on any topology
+// the testbed file describes.
+func validatePortAssumptions(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	// isPortsCall reports whether expr is a call like dut.Ports().
+	isPortsCall := func(expr ast.Expr) bool {
+		call, ok := ast.Unparen(expr).(*ast.CallExpr)
+		if !ok || len(call.Args) != 0 {
+			return false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		return ok && sel.Sel.Name == "Ports"
+	}
+	// portCount returns the X.Ports() of len(X.Ports()).
+	portCount := func(expr ast.Expr) ast.Expr {
+		call, ok := ast.Unparen(expr).(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return nil
+		}
+		if fn, ok := call.Fun.(*ast.Ident); !ok || fn.Name != "len" || !isPortsCall(call.Args[0]) {
+			return nil
+		}
+		return call.Args[0]
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.IndexExpr:
+			if lit := intLiteral(node.Index); lit != nil && isPortsCall(node.X) {
+				suggest(errs, "port-assumption", fs.Position(node.Pos()), "use the testbed port ID instead, e.g. Port(t, \"port1\")", "port picked by position %s in %s", lit.Value, types.ExprString(node.X))
+			}
+		case *ast.BinaryExpr:
+			if node.Op != token.EQL && node.Op != token.NEQ {
+				return true
+			}
+			for _, pair := range [][2]ast.Expr{{node.X, node.Y}, {node.Y, node.X}} {
+				if ports := portCount(pair[0]); ports != nil && intLiteral(pair[1]) != nil {
+					suggest(errs, "port-assumption", fs.Position(node.Pos()), "require a minimum with < or >= and skip otherwise", "test assumes exactly %s ports in %s", intLiteral(pair[1]).Value, types.ExprString(ports))
+				}
+			}
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "SetPortSpeed" || len(node.Args) != 1 {
+				return true
+			}
+			if speed, ok := ast.Unparen(node.Args[0]).(*ast.SelectorExpr); ok && strings.Contains(speed.Sel.Name, "ETHERNET_SPEED_SPEED_") {
+				suggest(errs, "port-assumption", fs.Position(node.Pos()), "derive it from the testbed port, e.g. Port(t, \"port1\").Speed()", "port speed hardcoded as %s", speed.Sel.Name)
+			}
+		}
+		return true
+	})
+}
+
+// bindingFlagValue returns the flag name and path literal of call when it
+// gives a -binding or testbed flag a path: flag.String("binding", path, ...),
+// flag.StringVar(&p, "binding", path, ...) or flag.Set("binding", path), on
+// the flag package or a FlagSet.
+func bindingFlagValue(call *ast.CallExpr) (string, *ast.BasicLit) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", nil
+	}
+	var args []ast.Expr
+	switch {
+	case sel.Sel.Name == "String" && len(call.Args) == 3, sel.Sel.Name == "Set" && len(call.Args) == 2:
+		args = call.Args[:2]
+	case sel.Sel.Name == "StringVar" && len(call.Args) == 4:
+		args = call.Args[1:3]
+	default:
+		return "", nil
+	}
+	name, ok := stringLiteral(args[0])
+	if !ok || !strings.Contains(strings.ToLower(name), "binding") && !strings.Contains(strings.ToLower(name), "testbed") {
+		return "", nil
+	}
+	if _, ok := stringLiteral(args[1]); !ok {
+		return "", nil
+	}
+	return name, ast.Unparen(args[1]).(*ast.BasicLit)
+}
+
+// validateBindingRefs flags -binding and testbed flag defaults naming files
+// that do not exist, which otherwise only fail once the test reserves the
+// testbed. Paths are resolved against the file's directory and its module
+// root; files provided at run time are listed in bindings.external.
+func (v *Validator) validateBindingRefs(path string, fs *token.FileSet, f *ast.File, mod *Module, errs *[]Issue) {
+	external := v.cfg.Bindings.External
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		name, lit := bindingFlagValue(call)
+		if lit == nil {
+			return true
+		}
+		ref, _ := stringLiteral(lit)
+		// Empty defaults and paths built from the environment are resolved
+		// at run time.
+		if ref == "" || strings.Contains(ref, "$") || matchAnyGlob(external, ref) {
+			return true
+		}
+		candidates := []string{ref}
+		if !filepath.IsAbs(ref) {
+			candidates = []string{filepath.Join(filepath.Dir(path), ref)}
+			if mod != nil {
+				candidates = append(candidates, filepath.Join(mod.Dir, ref))
+			}
+		}
+		for _, c := range candidates {
+			if matches, _ := filepath.Glob(c); len(matches) > 0 {
+				return true
+			}
+		}
+		suggest(errs, "binding-ref", fs.Position(lit.Pos()), "fix the path or list it under bindings.external in the config", "-%s path %s matches no file in the repo", name, lit.Value)
+		return true
+	})
+}
+
+// validateBuildConstraints flags //go:build and // +build lines in test
+// files that are not listed in buildConstraints.allowed. A test built only
+// on some platforms drops out of coverage accounting everywhere else;
+// platform differences belong in deviations.
+func (v *Validator) validateBuildConstraints(fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	allowed := make(map[string]bool)
+	for _, expr := range v.cfg.BuildConstraints.Allowed {
+		if c, err := constraint.Parse("//go:build " + expr); err == nil {
+			allowed[c.String()] = true
+		}
+	}
+	for _, group := range f.Comments {
+		if group.Pos() >= f.Package {
+			break
+		}
+		for _, c := range group.List {
+			if !constraint.IsGoBuild(c.Text) && !constraint.IsPlusBuild(c.Text) {
+				continue
+			}
+			expr, err := constraint.Parse(c.Text)
+			if err != nil || allowed[expr.String()] {
+				continue
+			}
+			// The // +build line beside a //go:build line repeats it.
+			allowed[expr.String()] = true
+			suggest(errs, "build-constraint", fs.Position(c.Pos()), "use a deviation for the platform difference or list the constraint under buildConstraints.allowed in the config", "build constraint %q hides the test from coverage accounting where it does not hold", expr.String())
+		}
+	}
+}
+
+// deviceNames maps the ondatra device kinds to the pattern their variables
+// are named by and the names it accepts, for the device-name rule.
+var deviceNames = map[string]struct {
+	re    *regexp.Regexp
+	names string
+}{
+	"*ondatra.DUTDevice": {regexp.MustCompile(`^dut\d*$`), "dut, or dut1, dut2, ... when the test uses several"},
+	"*ondatra.ATEDevice": {regexp.MustCompile(`^ate\d*$`), "ate, or ate1, ate2, ... when the test uses several"},
+	"*otg.OTG":           {regexp.MustCompile(`^otg\d*$`), "otg, or otg1, otg2, ... when the test uses several"},
+}
+
+// deviceKind returns the device type of t, a key of deviceNames, or "".
+func deviceKind(t types.Type) string {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return ""
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	switch path, name := named.Obj().Pkg().Path(), named.Obj().Name(); {
+	case path == "github.com/openconfig/ondatra" && (name == "DUTDevice" || name == "ATEDevice"):
+		return "*ondatra." + name
+	case path == "github.com/openconfig/ondatra/otg" && name == "OTG":
+		return "*otg.OTG"
+	}
+	return ""
+}
+
+// deviceKindExpr is deviceKind for code whose imports did not type-check:
+// it recognizes *ondatra.DUTDevice and the like, ondatra.DUT(t, ...) and
+// ondatra.ATE(t, ...) calls, and ate.OTG() calls.
+func deviceKindExpr(expr ast.Expr) string {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.StarExpr:
+		sel, ok := e.X.(*ast.SelectorExpr)
+		if !ok {
+			return ""
+		}
+		if pkg, ok := sel.X.(*ast.Ident); ok {
+			if kind := "*" + pkg.Name + "." + sel.Sel.Name; deviceNames[kind].re != nil {
+				return kind
+			}
+		}
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return ""
+		}
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "ondatra" {
+			switch sel.Sel.Name {
+			case "DUT":
+				return "*ondatra.DUTDevice"
+			case "ATE":
+				return "*ondatra.ATEDevice"
+			}
+		}
+		if sel.Sel.Name == "OTG" && len(e.Args) == 0 {
+			return "*otg.OTG"
+		}
+	}
+	return ""
+}
+
+// validateDeviceNames flags DUT, ATE and OTG variables and parameters named
+// other than dut, ate and otg (with an optional number), which makes tests
+// harder to read side by side.
+func validateDeviceNames(path string, fs *token.FileSet, f *ast.File, typesInfo *types.Info, errs *[]Issue) {
+	check := func(ident *ast.Ident, typeExpr, value ast.Expr) {
+		if ident == nil || ident.Name == "_" {
+			return
+		}
+		kind := ""
+		if typesInfo != nil {
+			if obj := typesInfo.Defs[ident]; obj != nil {
+				kind = deviceKind(obj.Type())
+			}
+		}
+		if kind == "" && typeExpr != nil {
+			kind = deviceKindExpr(typeExpr)
+		}
+		if kind == "" && value != nil {
+			kind = deviceKindExpr(value)
+		}
+		want, ok := deviceNames[kind]
+		if !ok || want.re.MatchString(ident.Name) {
+			return
+		}
+		suggest(errs, "device-name", fs.Position(ident.Pos()), fmt.Sprintf("name it %s", want.names), "%s variable %q has a non-standard name", kind, ident.Name)
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE {
+				return true
+			}
+			for i, lhs := range n.Lhs {
+				ident, _ := lhs.(*ast.Ident)
+				// := redeclaring a variable defines nothing new.
+				if ident != nil && typesInfo != nil && typesInfo.Defs[ident] == nil {
+					continue
+				}
+				var value ast.Expr
+				if len(n.Lhs) == len(n.Rhs) {
+					value = n.Rhs[i]
+				}
+				check(ident, nil, value)
+			}
+		case *ast.ValueSpec:
+			for i, ident := range n.Names {
+				var value ast.Expr
+				if len(n.Names) == len(n.Values) {
+					value = n.Values[i]
+				}
+				check(ident, n.Type, value)
+			}
+		case *ast.FuncType:
+			if n.Params == nil {
+				return true
+			}
+			for _, field := range n.Params.List {
+				for _, ident := range field.Names {
+					check(ident, field.Type, nil)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// dualStackClaimRE matches a README claiming both address families.
+var dualStackClaimRE = regexp.MustCompile(`(?i)dual[- ]?stack`)
+
+// claimsDualStack reports whether the README.md in dir claims dual-stack
+// coverage: it says "dual-stack", or the section whose heading mentions
+// coverage names both IPv4 and IPv6.
+func claimsDualStack(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	if err != nil {
+		return false
+	}
+	if dualStackClaimRE.Match(data) {
+		return true
+	}
+	inCoverage := false
+	var v4, v6 bool
+	for line := range strings.Lines(string(data)) {
+		if strings.HasPrefix(line, "#") {
+			inCoverage = strings.Contains(strings.ToLower(line), "coverage")
+			continue
+		}
+		if inCoverage {
+			lower := strings.ToLower(line)
+			v4 = v4 || strings.Contains(lower, "ipv4")
+			v6 = v6 || strings.Contains(lower, "ipv6")
+		}
+	}
+	return v4 && v6
+}
+
+// addressFamily returns 4 or 6 when s is an IP address or prefix literal,
+// e.g. "192.0.2.1" or "2001:db8::/64", and 0 otherwise.
+func addressFamily(s string) int {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return 0
+		}
+		addr = prefix.Addr()
+	}
+	if addr.Is4() || addr.Is4In6() {
+		return 4
+	}
+	return 6
+}
+
+// validateDualStack flags tests whose README claims dual-stack coverage
+// while the code of the test only uses address literals of one family. It
+// runs once per test, from the file holding TestMain, over every file of
+// the package.
+func validateDualStack(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	var testMain *ast.FuncDecl
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "TestMain" {
+			testMain = fn
+		}
+	}
+	if testMain == nil || !claimsDualStack(filepath.Dir(path)) {
+		return
+	}
+
+	families := make(map[int]bool)
+	for _, file := range append([]*ast.File{f}, parseSiblings(fs, path, f.Name.Name)...) {
+		ast.Inspect(file, func(n ast.Node) bool {
+			if s, ok := n.(*ast.BasicLit); ok {
+				if lit, ok := stringLiteral(s); ok {
+					families[addressFamily(lit)] = true
+				}
+			}
+			return true
+		})
+	}
+	switch {
+	case families[4] && !families[6]:
+		suggest(errs, "dual-stack", fs.Position(testMain.Pos()), "add the IPv6 cases or correct the README", "README claims dual-stack coverage but the test only uses IPv4 addresses")
+	case families[6] && !families[4]:
+		suggest(errs, "dual-stack", fs.Position(testMain.Pos()), "add the IPv4 cases or correct the README", "README claims dual-stack coverage but the test only uses IPv6 addresses")
+	}
+}
+
+func validateDeviationUsage(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		// Skip cfgplugins package completely.
+		if file.Name != nil && file.Name.Name == "cfgplugins" {
+			return nil
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			pkg, ok := sel.X.(*ast.Ident)
+			if !ok || pkg.Name != "deviations" {
+				return true
+			}
+
+			pos := fset.Position(call.Pos())
+
+			suggest(errs, "deviation-usage", pos, "move this logic into cfgplugins to maintain test abstraction", "direct use of deviations.%s() detected", sel.Sel.Name)
+
+			return true
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+func validateFunctionCommentMatch(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+
+			// Ignore init().
+			if fn.Name.Name == "init" {
+				continue
+			}
+
+			// Skip functions without documentation comments.
+			if fn.Doc == nil || len(fn.Doc.List) == 0 {
+				continue
+			}
+
+			// First comment line.
+			comment := fn.Doc.List[0].Text
+
+			switch {
+			case strings.HasPrefix(comment, "//"):
+				comment = strings.TrimSpace(strings.TrimPrefix(comment, "//"))
+			case strings.HasPrefix(comment, "/*"):
+				comment = strings.TrimSpace(strings.TrimPrefix(comment, "/*"))
+				comment = strings.TrimSuffix(comment, "*/")
+			}
+
+			if comment == "" {
+				continue
+			}
+
+			fields := strings.Fields(comment)
+			if len(fields) == 0 {
+				continue
+			}
+
+			firstWord := fields[0]
+
+			// Exact match (case-sensitive).
+			if firstWord != fn.Name.Name {
+				pos := fset.Position(fn.Pos())
+
+				report(errs, "comment-name", pos, "function comment should start with %q but starts with %q", fn.Name.Name, firstWord)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+func validateVendorCheckInDeviation(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		type blockRange struct {
+			start token.Pos
+			end   token.Pos
+		}
+
+		var deviationBlocks []blockRange
+
+		// ------------------------------------------------------------------
+		// Pass 1: Collect all "if deviations.Xxx(...)" block ranges.
+		// ------------------------------------------------------------------
+		ast.Inspect(file, func(n ast.Node) bool {
+			ifStmt, ok := n.(*ast.IfStmt)
+			if !ok {
+				return true
+			}
+
+			call, ok := ifStmt.Cond.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			id, ok := sel.X.(*ast.Ident)
+			if !ok || id.Name != "deviations" {
+				return true
+			}
+
+			deviationBlocks = append(deviationBlocks, blockRange{
+				start: ifStmt.Body.Pos(),
+				end:   ifStmt.Body.End(),
+			})
+
+			return true
+		})
+
+		// ------------------------------------------------------------------
+		// Pass 2: Find dut.Vendor() usages.
+		// ------------------------------------------------------------------
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Vendor" {
+				return true
+			}
+
+			// Match only *.Vendor()
+			if _, ok := sel.X.(*ast.Ident); !ok {
+				// Handles dut.Vendor()
+			} else {
+				// also acceptable
+			}
+
+			insideDeviation := false
+			for _, b := range deviationBlocks {
+				if call.Pos() >= b.start && call.Pos() <= b.end {
+					insideDeviation = true
+					break
+				}
+			}
+
+			if insideDeviation {
+				return true
+			}
+
+			pos := fset.Position(call.Pos())
+			report(errs, "vendor-check", pos, "direct dut.Vendor() usage should be moved into a deviation")
+
+			return true
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+func validateLogInsteadOfError(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			ifStmt, ok := n.(*ast.IfStmt)
+			if !ok {
+				return true
+			}
+
+			// Only consider comparison conditions.
+			switch cond := ifStmt.Cond.(type) {
+			case *ast.BinaryExpr:
+				switch cond.Op {
+				case token.NEQ,
+					token.EQL,
+					token.GTR,
+					token.LSS,
+					token.GEQ,
+					token.LEQ:
+				default:
+					return true
+				}
+			default:
+				return true
+			}
+
+			if len(ifStmt.Body.List) != 1 {
+				return true
+			}
+
+			exprStmt, ok := ifStmt.Body.List[0].(*ast.ExprStmt)
+			if !ok {
+				return true
+			}
+
+			call, ok := exprStmt.X.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			id, ok := sel.X.(*ast.Ident)
+			if !ok || id.Name != "t" {
+				return true
+			}
+
+			switch sel.Sel.Name {
+			case "Log", "Logf", "Logln":
+				pos := fset.Position(call.Pos())
+				suggest(errs, "log-instead-of-error", pos, "consider using t.Errorf() instead", "validation failure uses %s()", sel.Sel.Name)
+			}
+
+			return true
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+func validateContextUsage(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			// Match: t.Context()
+			if sel.Sel.Name != "Context" {
+				return true
+			}
+
+			id, ok := sel.X.(*ast.Ident)
+			if !ok || id.Name != "t" {
+				return true
+			}
+
+			pos := fset.Position(call.Pos())
+			suggest(errs, "t-context", pos, "use context.Background() or pass a context for Go 1.22/1.23 compatibility", "avoid using t.Context()")
+
+			return true
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+// issueTrackerRE matches links to a bug in the issue trackers.
+var issueTrackerRE = regexp.MustCompile(`https://(issuetracker\.google\.com/\d+|partnerissuetracker\.corp\.google\.com/.*/issues/\d+)`)
+
+func validateDeviationComment(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+
+			// Only validate deviation accessor functions.
+			if !strings.HasSuffix(fn.Name.Name, "Unsupported") {
+				continue
+			}
+
+			pos := fset.Position(fn.Pos())
+
+			if fn.Doc == nil {
+				report(errs, "deviation-comment", pos, "deviation function %q is missing a documentation comment", fn.Name.Name)
+				continue
+			}
+
+			comment := fn.Doc.Text()
+
+			// ------------------------------------------------------------------
+			// Check issue tracker.
+			// ------------------------------------------------------------------
+			if !issueTrackerRE.MatchString(comment) {
+				report(errs, "deviation-comment", pos, "deviation comment for %q is missing a \"Tracked at: https://issuetracker.google.com/<id>\" line", fn.Name.Name)
+			}
+
+			// ------------------------------------------------------------------
+			// Check incorrect OC path.
+			// ------------------------------------------------------------------
+			if strings.Contains(comment, "global-filter-policy") {
+				suggest(errs, "deviation-comment", pos, "use \"global-filter\"", "deviation comment for %q contains incorrect path \"global-filter-policy\"", fn.Name.Name)
+			}
+
+			// ------------------------------------------------------------------
+			// First comment line should start with function name.
+			// ------------------------------------------------------------------
+			first := ""
+			if len(fn.Doc.List) > 0 {
+				first = strings.TrimSpace(strings.TrimPrefix(fn.Doc.List[0].Text, "//"))
+			}
+
+			if !strings.HasPrefix(first, fn.Name.Name+" ") &&
+				first != fn.Name.Name {
+				report(errs, "deviation-comment", pos, "first comment line should start with %q", fn.Name.Name)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+func validateConfigurePoliciesSignature(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		// Skip deviations.go files.
+		if filepath.Base(path) == "deviations.go" {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		funcMap := collectFunctionInfo(file)
+
+		validateFunctionSignatures(fset, funcMap, errs)
+		validateHelperCalls(file, fset, funcMap, errs)
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+	return nil
+}
+
+type functionInfo struct {
+	Name        string
+	HasTestingT bool
+	ParamCount  int
+	Decl        *ast.FuncDecl
+	IsMethod    bool
+}
+
+// collectFunctionInfo collects metadata for all local functions in a Go file.
+func collectFunctionInfo(file *ast.File) map[string]functionInfo {
+	funcs := make(map[string]functionInfo)
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+
+		info := functionInfo{
+			Name: fn.Name.Name,
+			Decl: fn,
+		}
+
+		if fn.Type.Params != nil {
+			info.ParamCount = len(fn.Type.Params.List)
+		}
+
+		info.HasTestingT = functionHasTestingT(fn)
+		info.IsMethod = fn.Recv != nil
+		funcs[fn.Name.Name] = info
+	}
+
+	return funcs
+}
+
+// functionHasTestingT reports whether the first parameter of fn is
+// t *testing.T.
+func functionHasTestingT(fn *ast.FuncDecl) bool {
+	if fn == nil || fn.Type.Params == nil || len(fn.Type.Params.List) == 0 {
+		return false
+	}
+
+	param := fn.Type.Params.List[0]
+
+	if len(param.Names) == 0 || param.Names[0].Name != "t" {
+		return false
+	}
+
+	star, ok := param.Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	return pkg.Name == "testing" && sel.Sel.Name == "T"
+}
+
+// validateFunctionSignatures validates that helper functions accepting
+// *testing.T declare it as the first parameter named "t".
+func validateFunctionSignatures(
+	fset *token.FileSet,
+	funcs map[string]functionInfo,
+	errs *[]Issue,
+) {
+	for _, info := range funcs {
+		fn := info.Decl
+		if fn == nil || fn.Name.Name == "TestMain" {
+			continue
+		}
+
+		// Skip methods.
+		if fn.Recv != nil {
+			continue
+		}
+
+		// Skip functions without parameters.
+		if fn.Type.Params == nil || len(fn.Type.Params.List) == 0 {
+			continue
+		}
+
+		if info.HasTestingT {
+			if !hasTParameter(fn) {
+				pos := fset.Position(fn.Pos())
+				report(errs, "testing-t-param", pos, "function %q should have a parameter named t of type *testing.T", fn.Name.Name)
+			}
+		} else {
+			if !hasTParameter(fn) {
+				pos := fset.Position(fn.Pos())
+				report(errs, "testing-t-param", pos, "function %q should have a parameter named t", fn.Name.Name)
+			}
+		}
+	}
+}
+
+// hasTParameter returns true if the function has a parameter named "t".
+// If requireTestingT is true, the parameter must be of type *testing.T.
+func hasTParameter(fn *ast.FuncDecl) bool {
+	if fn == nil || fn.Type == nil || fn.Type.Params == nil {
+		return false
+	}
+
+	for _, field := range fn.Type.Params.List {
+		star, ok := field.Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+
+		sel, ok := star.X.(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		if pkg.Name == "testing" && sel.Sel.Name == "T" {
+			// Verify one of the parameter names is "t".
+			for _, name := range field.Names {
+				if name.Name == "t" {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// validateHelperCalls validates that local helper functions expecting
+// t *testing.T are always invoked with t as their first argument.
+func validateHelperCalls(
+	file *ast.File,
+	fset *token.FileSet,
+	funcs map[string]functionInfo,
+	errs *[]Issue,
+) {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			ident, ok := call.Fun.(*ast.Ident)
+			if !ok {
+				// Skip selector expressions:
+				// gnmi.Replace(...)
+				// t.Helper(...)
+				// fmt.Sprintf(...)
+				return true
+			}
+
+			callee, ok := funcs[ident.Name]
+			if !ok {
+				return true
+			}
+
+			if callee.IsMethod || !callee.HasTestingT {
+				return true
+			}
+
+			// Find where t *testing.T appears in the callee signature.
+			tIndex := -1
+			paramIndex := 0
+
+			if callee.Decl.Type.Params != nil {
+				for _, field := range callee.Decl.Type.Params.List {
+					for range field.Names {
+						if isTestingTType(field.Type) {
+							tIndex = paramIndex
+							break
+						}
+						paramIndex++
+					}
+					if tIndex != -1 {
+						break
+					}
+				}
+			}
+
+			if tIndex == -1 {
+				return true
+			}
+
+			callPos := fset.Position(call.Pos())
+
+			// Missing argument.
+			if len(call.Args) <= tIndex {
+				report(errs, "testing-t-param", callPos, "function %q expects parameter t *testing.T", ident.Name)
+				return true
+			}
+
+			arg, ok := call.Args[tIndex].(*ast.Ident)
+			if !ok || arg.Name != "t" {
+				report(errs, "testing-t-param", callPos, "function %q should be called with t for parameter %d", ident.Name, tIndex+1)
+			}
+
+			return true
+		})
+	}
+}
+
+func isTestingTType(expr ast.Expr) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	return pkg.Name == "testing" && sel.Sel.Name == "T"
+}
+
+func validateMagicNumbers(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		// Collect constant names.
+		constNames := make(map[string]bool)
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for _, name := range vs.Names {
+					constNames[name.Name] = true
+				}
+			}
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+
+			lit, ok := n.(*ast.BasicLit)
+			if !ok || lit.Kind != token.INT {
+				return true
+			}
+
+			// Ignore common values.
+			switch lit.Value {
+			case "0", "1", "-1":
+				return true
+			}
+
+			// Ignore constant declarations.
+			parentConst := false
+			ast.Inspect(file, func(parent ast.Node) bool {
+				gen, ok := parent.(*ast.GenDecl)
+				if ok && gen.Tok == token.CONST {
+					if lit.Pos() >= gen.Pos() && lit.End() <= gen.End() {
+						parentConst = true
+						return false
+					}
+				}
+				return true
+			})
+			if parentConst {
+				return true
+			}
+
+			// Ignore array declarations.
+			if arr, ok := n.(*ast.ArrayType); ok {
+				_ = arr
+				return true
+			}
+
+			pos := fset.Position(lit.Pos())
+
+			suggest(errs, "magic-number", pos, "define a named constant instead", "magic number %s detected", lit.Value)
+
+			return true
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+// sharedImporter resolves imports for the type-aware rules. It caches every
+// package it loads, so it is shared across files.
+var sharedImporter = importer.Default()
+
+// typeCheckFile type-checks a single file and returns whatever type
+// information could be recovered. Unresolvable imports and references to
+// other files of the package are tolerated; such expressions are simply left
+// without a type.
+func typeCheckFile(fset *token.FileSet, file *ast.File) *types.Info {
+	return typeCheckFiles(fset, []*ast.File{file})
+}
+
+// typeCheckFiles is like typeCheckFile for several files of one package.
+func typeCheckFiles(fset *token.FileSet, files []*ast.File) *types.Info {
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+
+	conf := types.Config{
+		Importer: sharedImporter,
+		Error:    func(error) {},
+	}
+	_, _ = conf.Check(files[0].Name.Name, fset, files, info)
+
+	return info
+}
+
+func validateFloatEquality(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		typesInfo := typeCheckFile(fset, file)
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			bin, ok := n.(*ast.BinaryExpr)
+			if !ok || (bin.Op != token.EQL && bin.Op != token.NEQ) {
+				return true
+			}
+
+			// Comparisons folded at compile time are exact.
+			if tv, ok := typesInfo.Types[bin]; ok && tv.Value != nil {
+				return true
+			}
+
+			if !isFloatExpr(typesInfo, bin.X) && !isFloatExpr(typesInfo, bin.Y) {
+				return true
+			}
+
+			pos := fset.Position(bin.OpPos)
+			suggest(errs, "float-equality", pos, "use a tolerance-based helper or cmpopts.EquateApprox instead", "floating-point values compared with %s", bin.Op)
+
+			return true
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+// isFloatExpr reports whether expr has a floating-point type. When the type
+// checker could not resolve expr, float literals and float32()/float64()
+// conversions are still recognized.
+func isFloatExpr(typesInfo *types.Info, expr ast.Expr) bool {
+	if t := typesInfo.TypeOf(expr); t != nil {
+		if basic, ok := t.Underlying().(*types.Basic); ok && basic.Kind() != types.Invalid {
+			return basic.Info()&types.IsFloat != 0
+		}
+	}
+
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.BasicLit:
+		return e.Kind == token.FLOAT
+	case *ast.CallExpr:
+		if ident, ok := e.Fun.(*ast.Ident); ok {
+			return ident.Name == "float32" || ident.Name == "float64"
+		}
+	}
+
+	return false
+}
+
+// validateInjectableClock flags calls of time.Now and time.Since, which tie
+// timing logic to the wall clock. Test files are only checked with the
+// ClockInTests option.
+func (v *Validator) validateInjectableClock(path string, fs *token.FileSet, f *ast.File, typesInfo *types.Info, errs *[]Issue) {
+	if isTestFile(path) && !v.opts.ClockInTests {
+		return
+	}
+
+	isTimeCall := timeCallMatcher(f, typesInfo)
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		for _, name := range []string{"Now", "Since"} {
+			if isTimeCall(call, name) {
+				suggest(errs, "injectable-clock", fs.Position(call.Pos()), "accept a clock or now func() time.Time so the timing logic can be unit-tested", "direct time.%s() call", name)
+			}
+		}
+
+		return true
+	})
+}
+
+func validateFunctionOrder(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		// Top-level functions in declaration order; methods follow their type.
+		var funcs []*ast.FuncDecl
+		index := make(map[string]int)
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil {
+				continue
+			}
+			index[fn.Name.Name] = len(funcs)
+			funcs = append(funcs, fn)
+		}
+
+		// firstCaller maps each function to the earliest declared function
+		// that calls it.
+		firstCaller := make(map[string]int)
+		for i, fn := range funcs {
+			if fn.Body == nil {
+				continue
+			}
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				ident, ok := call.Fun.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				if _, local := index[ident.Name]; !local {
+					return true
+				}
+				if _, seen := firstCaller[ident.Name]; !seen {
+					firstCaller[ident.Name] = i
+				}
+				return true
+			})
+		}
+
+		for i, fn := range funcs {
+			if fn.Name.IsExported() || fn.Name.Name == "init" || fn.Name.Name == "main" {
+				continue
+			}
+
+			// A helper placed after its first caller is fine wherever it is.
+			if caller, ok := firstCaller[fn.Name.Name]; ok && caller < i {
+				continue
+			}
+
+			for _, later := range funcs[i+1:] {
+				if !later.Name.IsExported() {
+					continue
+				}
+				pos := fset.Position(fn.Pos())
+				suggest(errs, "function-order", pos, "declare exported functions first or place the helper right after its first caller", "helper %q is declared before exported function %q", fn.Name.Name, later.Name.Name)
+				break
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+func validatePackageClauseComments(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		for _, group := range file.Comments {
+			if group.Pos() >= file.Package {
+				break
+			}
+
+			// Build constraints must be followed by a blank line, otherwise
+			// they end up in the package doc.
+			hasConstraint := false
+			for i, c := range group.List {
+				if !isBuildConstraint(c.Text) {
+					continue
+				}
+				hasConstraint = true
+
+				if i+1 < len(group.List) && isBuildConstraint(group.List[i+1].Text) {
+					continue
+				}
+				if i+1 < len(group.List) || group == file.Doc {
+					pos := fset.Position(c.Pos())
+					report(errs, "package-comment", pos, "build constraint must be followed by a blank line")
+				}
+			}
+
+			// Only the group directly above the package clause becomes the
+			// package doc.
+			if group != file.Doc || hasConstraint {
+				continue
+			}
+
+			text := strings.TrimSpace(group.Text())
+			if text == "" || isPackageDoc(file.Name.Name, text) {
+				continue
+			}
+
+			pos := fset.Position(group.Pos())
+			suggest(errs, "package-comment", pos, "separate it with a blank line or rewrite it as the package doc", "comment directly above the package clause becomes the package doc but does not start with \"Package %s\"", file.Name.Name)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+// isBuildConstraint reports whether a comment line is a build constraint.
+func isBuildConstraint(text string) bool {
+	return strings.HasPrefix(text, "//go:build") || strings.HasPrefix(text, "// +build")
+}
+
+// isPackageDoc reports whether text reads like the doc of package pkg.
+// Commands may also be documented as "Command x" or "Binary x".
+func isPackageDoc(pkg, text string) bool {
+	if strings.HasPrefix(text, "Package "+pkg) {
+		return true
+	}
+	return pkg == "main" && (strings.HasPrefix(text, "Command ") || strings.HasPrefix(text, "Binary "))
+}
+
+func (v *Validator) validateUnkeyedCompositeLiterals(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		typesInfo := typeCheckFile(fset, file)
+
+		// fixed takes the findings -fix resolves, which are not reported.
+		var edits []TextEdit
+		var fixed []Issue
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok || len(lit.Elts) == 0 {
+				return true
+			}
+			if _, keyed := lit.Elts[0].(*ast.KeyValueExpr); keyed {
+				return true
+			}
+
+			// Without type information pkg.Type{...} may as well be a slice
+			// or map, so only resolved struct types are checked.
+			typeName, fields := importedStructFields(typesInfo, file, lit)
+			if typeName == "" {
+				return true
+			}
+
+			var fix []TextEdit
+			if len(fields) == len(lit.Elts) {
+				for i, elt := range lit.Elts {
+					offset := fset.Position(elt.Pos()).Offset
+					fix = append(fix, TextEdit{Start: offset, End: offset, NewText: fields[i] + ": "})
+				}
+			}
+
+			target := errs
+			if v.opts.Fix && fix != nil {
+				edits = append(edits, fix...)
+				target = &fixed
+			}
+
+			suggest(target, "unkeyed-literal", fset.Position(lit.Pos()), "name the fields so upstream additions do not break it silently", "composite literal of imported struct %s uses unkeyed fields", typeName)
+			(*target)[len(*target)-1].Fix = fix
+			return true
+		})
+
+		if err := applyEdits(path, edits); err != nil {
+			report(errs, "unkeyed-literal", token.Position{Filename: path}, "applying fixes: %v", err)
+			return nil
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+// importedStructFields returns the qualified name and field names of the
+// struct type built by lit when that type is declared in another package.
+// It returns an empty name when the type is local or could not be resolved.
+func importedStructFields(typesInfo *types.Info, file *ast.File, lit *ast.CompositeLit) (string, []string) {
+	t := typesInfo.TypeOf(lit)
+	if t == nil {
+		return "", nil
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() == file.Name.Name {
+		return "", nil
+	}
+
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return "", nil
+	}
+
+	fields := make([]string, st.NumFields())
+	for i := range fields {
+		fields[i] = st.Field(i).Name()
+	}
+	return named.Obj().Pkg().Name() + "." + named.Obj().Name(), fields
+}
+
+// validateConfigStructLiterals requires the config structs passed to a
+// function, the struct parameters the struct-param rule asks for, to be
+// constructed with keyed fields at the call and to set the fields tagged
+// fpvalidator:"required".
+func validateConfigStructLiterals(root string, errs *[]Issue) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, pkgFiles, err := parsePackageOf(fset, path)
+		if err != nil {
+			return err
+		}
+
+		typesInfo := typeCheckFiles(fset, pkgFiles)
+
+		// Config structs are the literals passed for a parameter of their own
+		// struct type. Builtins such as append and variadic parameters such
+		// as fmt.Println's ...any take any value and are left out.
+		configs := make(map[*ast.CompositeLit]*types.Named)
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if ident, ok := ast.Unparen(call.Fun).(*ast.Ident); ok {
+				if _, builtin := typesInfo.Uses[ident].(*types.Builtin); builtin {
+					return true
+				}
+			}
+			sig, ok := typesInfo.TypeOf(call.Fun).(*types.Signature)
+			if !ok {
+				return true
+			}
+			for i, arg := range call.Args {
+				if i >= sig.Params().Len() || (sig.Variadic() && i >= sig.Params().Len()-1) {
+					break
+				}
+				if u, ok := arg.(*ast.UnaryExpr); ok && u.Op == token.AND {
+					arg = u.X
+				}
+				lit, ok := arg.(*ast.CompositeLit)
+				if !ok {
+					continue
+				}
+				named := namedStruct(typesInfo.TypeOf(lit))
+				if named != nil && named == namedStruct(sig.Params().At(i).Type()) {
+					configs[lit] = named
+				}
+			}
+			return true
+		})
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+
+			named, ok := configs[lit]
+			if !ok {
+				return true
+			}
+			st := named.Underlying().(*types.Struct)
+			pos := fset.Position(lit.Pos())
+
+			keys := make(map[string]bool)
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					report(errs, "config-struct-literal", pos, "config struct %s must be constructed with keyed fields", named.Obj().Name())
+					return true
+				}
+				if ident, ok := kv.Key.(*ast.Ident); ok {
+					keys[ident.Name] = true
+				}
+			}
+
+			for i := 0; i < st.NumFields(); i++ {
+				field := st.Field(i)
+				if !isRequiredField(st.Tag(i)) || keys[field.Name()] {
+					continue
+				}
+				report(errs, "config-struct-literal", pos, "config struct %s is missing required field %s", named.Obj().Name(), field.Name())
+			}
+
+			return true
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(*errs) > 0 {
+		return fmt.Errorf("%s", joinIssues(*errs))
+	}
+
+	return nil
+}
+
+// parsePackageOf parses the file at path together with the other files of
+// its package in the same directory. The file itself is returned first.
+func parsePackageOf(fset *token.FileSet, path string) (*ast.File, []*ast.File, error) {
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, append([]*ast.File{file}, parseSiblings(fset, path, file.Name.Name)...), nil
+}
+
+// parseSiblings parses the other files of package pkg that live next to path.
+// Files that fail to parse are skipped.
+func parseSiblings(fset *token.FileSet, path, pkg string) []*ast.File {
+	var files []*ast.File
+	siblings, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.go"))
+	for _, sibling := range siblings {
+		if filepath.Clean(sibling) == filepath.Clean(path) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, sibling, nil, parser.ParseComments)
+		if err != nil || f.Name.Name != pkg {
+			continue
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// namedStruct returns the named struct type behind t or *t, if any.
+func namedStruct(t types.Type) *types.Named {
+	if t == nil {
+		return nil
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return nil
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+	return named
+}
+
+// isRequiredField reports whether a struct tag carries fpvalidator:"required".
+func isRequiredField(tag string) bool {
+	for _, opt := range strings.Split(reflect.StructTag(tag).Get("fpvalidator"), ",") {
+		if strings.TrimSpace(opt) == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// getPrefixExempt reports whether fn may keep its Get prefix under the
+// configured exemptions: its file matches an exempt glob, its receiver type
+// is exempt, or it implements a method of an exempt interface. Interfaces
+// that cannot be loaded are reported once.
+func (v *Validator) getPrefixExempt(path string, fset *token.FileSet, file *ast.File, fn *ast.FuncDecl, errs *[]Issue) bool {
+	cfg := v.cfg.GetPrefix
+	if matchAnyGlob(cfg.Files, path) {
+		return true
+	}
+
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return false
+	}
+
+	recvName := receiverTypeName(fn.Recv.List[0].Type)
+	for _, r := range cfg.Receivers {
+		if strings.TrimPrefix(r, "*") == recvName {
+			return true
+		}
+	}
+
+	if len(cfg.Interfaces) == 0 {
+		return false
+	}
+
+	typesInfo := typeCheckFiles(fset, append([]*ast.File{file}, parseSiblings(fset, path, file.Name.Name)...))
+	obj, ok := typesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+	recv := obj.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+
+	dir := filepath.Dir(path)
+	if mod := moduleOf(dir); mod != nil {
+		dir = mod.Dir
+	}
+	for _, ref := range cfg.Interfaces {
+		key := [2]string{dir, ref}
+		lookup, ok := getPrefixInterfaces[key]
+		if !ok {
+			lookup.iface, lookup.err = loadInterface(dir, ref)
+			getPrefixInterfaces[key] = lookup
+			if lookup.err != nil {
+				report(errs, "get-prefix", token.Position{Filename: path}, "getPrefix.interfaces: %v", lookup.err)
+			}
+		}
+		if lookup.iface == nil {
+			continue
+		}
+		if m, _, _ := types.LookupFieldOrMethod(lookup.iface, false, nil, fn.Name.Name); m == nil {
+			continue
+		}
+		if hasMethods(recv.Type(), lookup.iface) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// interfaceLookup is the result of loading a getPrefix.interfaces entry.
+type interfaceLookup struct {
+	iface *types.Interface
+	err   error
+}
+
+// getPrefixInterfaces caches the getPrefix.interfaces entries by module
+// directory and reference.
+var getPrefixInterfaces = make(map[[2]string]interfaceLookup)
+
+// hasMethods reports whether t or *t has every method of iface. Only the
+// names are compared: t is type-checked without the packages of its module,
+// so its method signatures need not resolve to the types iface uses.
+func hasMethods(t types.Type, iface *types.Interface) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	for i := 0; i < iface.NumMethods(); i++ {
+		if m, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), false, nil, iface.Method(i).Name()); m == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// receiverTypeName returns the base type name of a method receiver.
+func receiverTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(e.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(e.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+// loadInterface resolves an "import/path.Name" reference to an interface,
+// loading the package as the module in dir sees it.
+func loadInterface(dir, ref string) (*types.Interface, error) {
+	dot := strings.LastIndex(ref, ".")
+	if dot <= 0 || strings.HasSuffix(ref[:dot], "/") {
+		return nil, fmt.Errorf("%q is not of the form import/path.Name", ref)
+	}
+
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedTypes | packages.NeedImports | packages.NeedDeps, Dir: dir}
+	pkgs, err := packages.Load(cfg, ref[:dot])
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", ref, err)
+	}
+	if len(pkgs) != 1 || pkgs[0].Types == nil {
+		return nil, fmt.Errorf("loading %s: package not found", ref)
+	}
+	if len(pkgs[0].Errors) > 0 {
+		return nil, fmt.Errorf("loading %s: %v", ref, pkgs[0].Errors[0])
+	}
+
+	obj := pkgs[0].Types.Scope().Lookup(ref[dot+1:])
+	if obj == nil {
+		return nil, fmt.Errorf("%s: no type %s in %s", ref, ref[dot+1:], ref[:dot])
+	}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an interface", ref)
+	}
+	return iface, nil
+}
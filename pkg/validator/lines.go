@@ -0,0 +1,114 @@
+package validator
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+)
+
+// defaultMaxLineSize is the longest line, in bytes, the line-based rules
+// look at in full unless the config sets maxLineSize.
+const defaultMaxLineSize = 1 << 20
+
+// A cut line only means part of the file went unchecked.
+func init() { defaultSeverities["long-line"] = SeverityWarning }
+
+// maxLineSize returns the configured line size limit.
+func (v *Validator) maxLineSize() int {
+	if v.cfg.MaxLineSize > 0 {
+		return v.cfg.MaxLineSize
+	}
+	return defaultMaxLineSize
+}
+
+// sniffSize is how much of a file is looked at to tell whether it is text.
+const sniffSize = 64 << 10
+
+// unscannedReason returns why the line-based rules skip path, or "" when
+// they read it. Binary files and minified ones, a single line filling the
+// sniffed prefix, only produce garbage findings.
+func (v *Validator) unscannedReason(path string) string {
+	if reason, ok := v.unscanned[path]; ok {
+		return reason
+	}
+
+	reason := ""
+	if f, err := os.Open(path); err == nil {
+		sniff := make([]byte, sniffSize)
+		n, _ := io.ReadFull(f, sniff)
+		f.Close()
+		sniff = sniff[:n]
+		switch {
+		case bytes.IndexByte(sniff, 0) >= 0:
+			reason = "binary file"
+		case n == sniffSize && bytes.Count(sniff, []byte("\n")) <= 1:
+			reason = "minified file"
+		}
+	}
+	if reason != "" && v.opts.Verbose != nil {
+		fmt.Fprintf(v.opts.Verbose, "%s: skipping line checks: %s\n", path, reason)
+	}
+	v.unscanned[path] = reason
+	return reason
+}
+
+// forEachLine calls fn with every line of path and its 1-based number.
+// Unlike bufio.Scanner it does not stop at long lines, such as embedded JSON
+// in generated files: a line longer than the limit is cut to it and reported
+// as long-line. Failures to read the file are reported as read-error.
+// Binary and minified files are skipped.
+func (v *Validator) forEachLine(path string, errs *[]Issue, fn func(lineNo int, line string)) {
+	if v.unscannedReason(path) != "" {
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		report(errs, "read-error", token.Position{Filename: path}, "cannot read file: %v", err)
+		return
+	}
+	defer f.Close()
+
+	limit := v.maxLineSize()
+	r := bufio.NewReader(f)
+	for lineNo := 1; ; lineNo++ {
+		line, truncated, err := readLine(r, limit)
+		if err != nil && !errors.Is(err, io.EOF) {
+			report(errs, "read-error", token.Position{Filename: path, Line: lineNo}, "cannot read file: %v", err)
+			return
+		}
+		if errors.Is(err, io.EOF) && line == "" {
+			return
+		}
+		if truncated {
+			report(errs, "long-line", token.Position{Filename: path, Line: lineNo}, "line is longer than %d bytes; only its start was checked", limit)
+		}
+		fn(lineNo, line)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readLine reads the next line from r without its line ending, keeping at
+// most limit bytes and skipping the rest. It returns io.EOF with the last
+// line when the input ends without a newline.
+func readLine(r *bufio.Reader, limit int) (line string, truncated bool, err error) {
+	var buf []byte
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			return string(buf), truncated, err
+		}
+		if room := limit - len(buf); len(chunk) > room {
+			chunk, truncated = chunk[:max(room, 0)], true
+		}
+		buf = append(buf, chunk...)
+		if !isPrefix {
+			return string(buf), truncated, nil
+		}
+	}
+}
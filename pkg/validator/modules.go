@@ -0,0 +1,178 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Module is the Go module a file belongs to.
+type Module struct {
+	// Dir is the directory holding its go.mod.
+	Dir string
+	// Path is the module path, e.g. "github.com/openconfig/featureprofiles".
+	Path string
+	// GoVersion is the go directive, e.g. "go1.22"; modules without one are
+	// treated as go1.16.
+	GoVersion string
+	// Workspace lists the modules of the go.work workspace the module is
+	// used by, itself included, or only the module outside a workspace.
+	Workspace []*Module
+}
+
+// modules caches the module found for each directory; nil marks directories
+// outside any module.
+var modules = make(map[string]*Module)
+
+// workspaces caches the modules used by each go.work directory; nil marks
+// directories without a go.work.
+var workspaces = make(map[string][]*Module)
+
+// moduleOf returns the module of the nearest go.mod above dir, or nil when
+// dir is not in a module. Nested modules end where their go.mod is, so a
+// monorepo resolves each file against its own module.
+func moduleOf(dir string) *Module {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	if m, ok := modules[dir]; ok {
+		return m
+	}
+
+	var m *Module
+	if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+		m = parseModule(dir, data)
+		m.Workspace = []*Module{m}
+		if ws := workspaceOf(dir); ws != nil {
+			m.Workspace = ws
+		}
+	} else if parent := filepath.Dir(dir); parent != dir {
+		m = moduleOf(parent)
+	}
+	modules[dir] = m
+	return m
+}
+
+// parseModule reads the module path and go version of a go.mod file.
+func parseModule(dir string, gomod []byte) *Module {
+	m := &Module{Dir: dir, GoVersion: "go1.16"}
+	f, err := modfile.ParseLax(filepath.Join(dir, "go.mod"), gomod, nil)
+	if err != nil {
+		return m
+	}
+	if f.Module != nil {
+		m.Path = f.Module.Mod.Path
+	}
+	if f.Go != nil {
+		m.GoVersion = "go" + f.Go.Version
+	}
+	return m
+}
+
+// workspaceOf returns the modules listed by the nearest go.work above dir
+// that uses dir itself, or nil.
+func workspaceOf(dir string) []*Module {
+	for workDir := dir; ; workDir = filepath.Dir(workDir) {
+		ws, ok := workspaces[workDir]
+		if !ok {
+			if data, err := os.ReadFile(filepath.Join(workDir, "go.work")); err == nil {
+				ws = parseWorkspace(workDir, data)
+			}
+			workspaces[workDir] = ws
+		}
+		for _, m := range ws {
+			if m.Dir == dir {
+				return ws
+			}
+		}
+		if filepath.Dir(workDir) == workDir {
+			return nil
+		}
+	}
+}
+
+// parseWorkspace reads the modules a go.work file uses.
+func parseWorkspace(dir string, gowork []byte) []*Module {
+	f, err := modfile.ParseWork(filepath.Join(dir, "go.work"), gowork, nil)
+	if err != nil {
+		return nil
+	}
+	var ws []*Module
+	for _, use := range f.Use {
+		modDir := filepath.Join(dir, filepath.FromSlash(use.Path))
+		data, err := os.ReadFile(filepath.Join(modDir, "go.mod"))
+		if err != nil {
+			continue
+		}
+		m := parseModule(modDir, data)
+		ws = append(ws, m)
+	}
+	for _, m := range ws {
+		m.Workspace = ws
+		modules[m.Dir] = m
+	}
+	return ws
+}
+
+// packageImportPath returns the import path of the package in dir, derived
+// from its module, or "" when dir is not in a module.
+func packageImportPath(dir string) string {
+	m := moduleOf(dir)
+	if m == nil || m.Path == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(m.Dir, mustAbs(dir))
+	if err != nil || rel == "." {
+		return m.Path
+	}
+	return m.Path + "/" + filepath.ToSlash(rel)
+}
+
+// moduleGoVersion returns the Go version declared by the module of dir, or
+// "" when dir is not in a module.
+func moduleGoVersion(dir string) string {
+	if m := moduleOf(dir); m != nil {
+		return m.GoVersion
+	}
+	return ""
+}
+
+// noteModule tells the Verbose writer when the walk enters the directory of
+// a module, e.g. a nested module of a monorepo.
+func (v *Validator) noteModule(dir string) {
+	if v.opts.Verbose == nil {
+		return
+	}
+	m := moduleOf(dir)
+	if m == nil || m.Dir != mustAbs(dir) {
+		return
+	}
+	if len(m.Workspace) > 1 {
+		fmt.Fprintf(v.opts.Verbose, "%s: module %s, one of %d in its go.work workspace\n", dir, m.Path, len(m.Workspace))
+	} else {
+		fmt.Fprintf(v.opts.Verbose, "%s: module %s\n", dir, m.Path)
+	}
+}
+
+// mustAbs returns the absolute form of path, or path when it has none.
+func mustAbs(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// LocalImport reports whether importPath is a package of m or of another
+// module of its workspace, rather than a dependency.
+func (m *Module) LocalImport(importPath string) bool {
+	for _, w := range m.Workspace {
+		if w.Path != "" && (importPath == w.Path || strings.HasPrefix(importPath, w.Path+"/")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package validator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// goPackage holds the parsed files of one package: all files of a
+// directory sharing a package clause, so x and x_test are separate.
+type goPackage struct {
+	Dir   string
+	Name  string
+	Files []*ast.File
+}
+
+// validatePackages runs the checks that span the files of a package, after
+// the per-file rules have run on each of paths.
+func validatePackages(paths []string) []Issue {
+	var errs []Issue
+	fs := token.NewFileSet()
+
+	var pkgs []*goPackage
+	byKey := make(map[string]*goPackage)
+	for _, path := range paths {
+		f, err := parser.ParseFile(fs, path, nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		dir := filepath.Dir(path)
+		key := dir + "\x00" + f.Name.Name
+		pkg, ok := byKey[key]
+		if !ok {
+			pkg = &goPackage{Dir: dir, Name: f.Name.Name}
+			byKey[key] = pkg
+			pkgs = append(pkgs, pkg)
+		}
+		pkg.Files = append(pkg.Files, f)
+	}
+
+	for _, pkg := range pkgs {
+		validateDuplicateFuncs(fs, pkg, &errs)
+		validateImportAliases(fs, pkg, &errs)
+		validatePackageDoc(fs, pkg, &errs)
+	}
+	validateTestMains(fs, pkgs, &errs)
+
+	return errs
+}
+
+// validateTestMains requires exactly one TestMain in each directory holding
+// tests. A package and its external _test package build into one test
+// binary, so they share the TestMain.
+func validateTestMains(fs *token.FileSet, pkgs []*goPackage, errs *[]Issue) {
+	var dirs []string
+	tests := make(map[string]*ast.FuncDecl)
+	mains := make(map[string][]*ast.FuncDecl)
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			if !isTestFile(fs.Position(f.Package).Filename) {
+				continue
+			}
+			for _, d := range f.Decls {
+				fn, ok := d.(*ast.FuncDecl)
+				if !ok || fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "Test") {
+					continue
+				}
+				if !containsString(dirs, pkg.Dir) {
+					dirs = append(dirs, pkg.Dir)
+				}
+				if fn.Name.Name == "TestMain" {
+					mains[pkg.Dir] = append(mains[pkg.Dir], fn)
+				} else if tests[pkg.Dir] == nil {
+					tests[pkg.Dir] = fn
+				}
+			}
+		}
+	}
+
+	for _, dir := range dirs {
+		fns := mains[dir]
+		if len(fns) == 0 {
+			pos := fs.Position(tests[dir].Pos())
+			report(errs, "test-structure", pos, "missing TestMain function for the tests in %s", dir)
+			continue
+		}
+		first := fs.Position(fns[0].Pos())
+		for _, fn := range fns[1:] {
+			pos := fs.Position(fn.Pos())
+			suggest(errs, "test-structure", pos, "keep one TestMain per test binary", "TestMain is already declared at %s:%d", first.Filename, first.Line)
+		}
+	}
+}
+
+// validateDuplicateFuncs flags top-level functions declared in more than
+// one file of a package.
+func validateDuplicateFuncs(fs *token.FileSet, pkg *goPackage, errs *[]Issue) {
+	seen := make(map[string]token.Position)
+	for _, f := range pkg.Files {
+		for _, d := range f.Decls {
+			fn, ok := d.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Name.Name == "init" || fn.Name.Name == "_" {
+				continue
+			}
+			pos := fs.Position(fn.Name.Pos())
+			first, ok := seen[fn.Name.Name]
+			if !ok {
+				seen[fn.Name.Name] = pos
+				continue
+			}
+			if first.Filename != pos.Filename {
+				report(errs, "duplicate-func", pos, "function %s is already declared in %s:%d", fn.Name.Name, first.Filename, first.Line)
+			}
+		}
+	}
+}
+
+// validateImportAliases requires every file of a package to import a given
+// path under the same name.
+func validateImportAliases(fs *token.FileSet, pkg *goPackage, errs *[]Issue) {
+	names := make(map[string]string)
+	for _, f := range pkg.Files {
+		for _, imp := range f.Imports {
+			importPath := strings.Trim(imp.Path.Value, `"`)
+			name := defaultImportName(importPath)
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			if name == "_" || name == "." {
+				continue
+			}
+			first, ok := names[importPath]
+			if !ok {
+				names[importPath] = name
+				continue
+			}
+			if name != first {
+				pos := fs.Position(imp.Pos())
+				report(errs, "import-alias", pos, "%q is imported as %s here but as %s elsewhere in package %s", importPath, name, first, pkg.Name)
+			}
+		}
+	}
+}
+
+// validatePackageDoc requires a package doc comment in one of the non-test
+// files of every package other than main.
+func validatePackageDoc(fs *token.FileSet, pkg *goPackage, errs *[]Issue) {
+	if pkg.Name == "main" || strings.HasSuffix(pkg.Name, "_test") {
+		return
+	}
+
+	var first *ast.File
+	for _, f := range pkg.Files {
+		if isTestFile(fs.Position(f.Package).Filename) {
+			continue
+		}
+		if f.Doc != nil && strings.TrimSpace(f.Doc.Text()) != "" {
+			return
+		}
+		if first == nil {
+			first = f
+		}
+	}
+
+	if first != nil {
+		pos := fs.Position(first.Package)
+		suggest(errs, "package-doc", pos, fmt.Sprintf("add a \"// Package %s ...\" comment to one of its files", pkg.Name), "package %s has no package doc", pkg.Name)
+	}
+}
+
+// defaultImportName guesses the package name of importPath from its last
+// element, skipping major version suffixes: "gopkg.in/yaml.v3" and
+// "example.com/foo/v2" give "yaml" and "foo".
+func defaultImportName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	if i := strings.IndexByte(name, '.'); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
@@ -0,0 +1,107 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"time"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/rpcplugin"
+)
+
+// pluginDefaultTimeout bounds a check call when the plugin sets no timeout.
+const pluginDefaultTimeout = 10 * time.Second
+
+// rpcPlugin is a running rule plugin. A check call that times out kills the
+// plugin, so it is started afresh for the next file.
+type rpcPlugin struct {
+	cfg     PluginConfig
+	timeout time.Duration
+	client  *rpcplugin.Client
+
+	// broken is set once restarting the plugin failed; it is skipped then.
+	broken bool
+}
+
+// startPlugins launches every plugin listed in the config.
+func (v *Validator) startPlugins(cfgs []PluginConfig) error {
+	for _, c := range cfgs {
+		timeout := pluginDefaultTimeout
+		if c.Timeout != "" {
+			d, err := time.ParseDuration(c.Timeout)
+			if err != nil {
+				return errors.Join(fmt.Errorf("plugin %s: timeout: %w", c.Name, err), v.stopPlugins())
+			}
+			timeout = d
+		}
+		client, err := rpcplugin.Start(c.Name, c.Path, c.Args...)
+		if err != nil {
+			return errors.Join(err, v.stopPlugins())
+		}
+		v.plugins = append(v.plugins, &rpcPlugin{cfg: c, timeout: timeout, client: client})
+	}
+	return nil
+}
+
+// stopPlugins shuts down every running plugin and returns the errors of
+// those that did not exit cleanly.
+func (v *Validator) stopPlugins() error {
+	var errs []error
+	for _, p := range v.plugins {
+		if p.broken {
+			continue
+		}
+		if err := p.client.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("plugin %s: %w", p.cfg.Name, err))
+		}
+	}
+	v.plugins = nil
+	return errors.Join(errs...)
+}
+
+// check passes one file to the plugin, limited by its timeout. A plugin
+// that timed out is killed by the call and started again.
+func (p *rpcPlugin) check(ctx context.Context, path string, src []byte) ([]rpcplugin.Finding, error) {
+	if p.broken {
+		return nil, nil
+	}
+	callCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	findings, err := p.client.Check(callCtx, path, src)
+	if err != nil && callCtx.Err() != nil && ctx.Err() == nil {
+		_ = p.client.Close()
+		client, serr := rpcplugin.Start(p.cfg.Name, p.cfg.Path, p.cfg.Args...)
+		if serr != nil {
+			p.broken = true
+			return nil, fmt.Errorf("%w; the plugin is disabled, starting it again failed: %v", err, serr)
+		}
+		p.client = client
+	}
+	return findings, err
+}
+
+func (v *Validator) validatePlugins(ctx context.Context, path string, errs *[]Issue) {
+	if len(v.plugins) == 0 {
+		return
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		report(errs, "plugin", token.Position{Filename: path}, "failed reading file for plugins")
+		return
+	}
+
+	for _, p := range v.plugins {
+		findings, err := p.check(ctx, path, src)
+		if err != nil {
+			report(errs, "plugin:"+p.cfg.Name, token.Position{Filename: path}, "%v", err)
+			continue
+		}
+		for _, f := range findings {
+			reportSuggestion(errs, "plugin:"+p.cfg.Name, token.Position{Filename: path, Line: f.Line}, fmt.Sprintf("%s (plugin %s)", f.Message, p.cfg.Name), f.Suggestion)
+		}
+	}
+}
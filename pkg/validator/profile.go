@@ -0,0 +1,60 @@
+package validator
+
+import (
+	"embed"
+	"fmt"
+	"maps"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// profileFiles holds the built-in profiles, one YAML config per profile.
+//
+//go:embed profiles/*.yaml
+var profileFiles embed.FS
+
+// Profiles returns the names of the built-in profiles.
+func Profiles() []string {
+	entries, _ := profileFiles.ReadDir("profiles")
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".yaml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyProfile returns cfg on top of the built-in profile name, which
+// pre-selects rules and severities. Settings of cfg win: its enable,
+// disable and tag lists add to the profile's and its rules entries replace
+// the profile's rule by rule. cfg may be nil.
+func ApplyProfile(cfg *Config, name string) (*Config, error) {
+	data, err := profileFiles.ReadFile(path.Join("profiles", name+".yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown profile %q, want one of %s", name, strings.Join(Profiles(), ", "))
+	}
+	profile := &Config{}
+	if err := yaml.Unmarshal(data, profile); err != nil {
+		return nil, fmt.Errorf("parsing profile %s: %w", name, err)
+	}
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	merged := *cfg
+	merged.Profile = name
+	merged.Enable = append(profile.Enable, cfg.Enable...)
+	merged.Disable = append(profile.Disable, cfg.Disable...)
+	merged.OnlyTags = append(profile.OnlyTags, cfg.OnlyTags...)
+	merged.SkipTags = append(profile.SkipTags, cfg.SkipTags...)
+	merged.Rules = make(map[string]RuleConfig)
+	maps.Copy(merged.Rules, profile.Rules)
+	maps.Copy(merged.Rules, cfg.Rules)
+	if merged.FailOn == "" {
+		merged.FailOn = profile.FailOn
+	}
+	return &merged, nil
+}
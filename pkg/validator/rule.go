@@ -0,0 +1,452 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// File is a parsed Go source file handed to each Rule.
+type File struct {
+	Path string
+	Fset *token.FileSet
+	AST  *ast.File
+
+	// v gives built-in rules access to the configuration.
+	v *Validator
+
+	// ctx is the context of the run, see Context.
+	ctx context.Context
+
+	// info and src cache TypesInfo and Source for the rules sharing f.
+	info *types.Info
+	src  []byte
+}
+
+// IsTest reports whether f is a _test.go file.
+func (f *File) IsTest() bool {
+	return isTestFile(f.Path)
+}
+
+// isTestFile reports whether path is a _test.go file. Rules and the
+// tests/nonTests rule settings all classify files through it.
+func isTestFile(path string) bool {
+	return strings.HasSuffix(path, "_test.go")
+}
+
+// Context returns the context of the run. Rules doing slow work, such as
+// calling out to other processes, stop when it is done.
+func (f *File) Context() context.Context {
+	if f.ctx == nil {
+		return context.Background()
+	}
+	return f.ctx
+}
+
+// TypesInfo returns the type information of f, type-checking it on first
+// use. References the checker cannot resolve, such as other files of the
+// package, are left without a type. Rules calling it declare NeedsTypes.
+func (f *File) TypesInfo() *types.Info {
+	if f.info == nil {
+		f.info = typeCheckFile(f.Fset, f.AST)
+	}
+	return f.info
+}
+
+// Module returns the Go module f belongs to, or nil outside a module. In a
+// go.work workspace it lists the other modules too.
+func (f *File) Module() *Module {
+	return moduleOf(filepath.Dir(f.Path))
+}
+
+// Source returns the contents of f. Rules calling it declare NeedsSource.
+func (f *File) Source() ([]byte, error) {
+	if f.src == nil {
+		src, err := os.ReadFile(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		f.src = src
+	}
+	return f.src, nil
+}
+
+// Rule is a check run on every Go file. Rules contributed outside the
+// built-in set live in their own file and call Register from an init
+// function:
+//
+//	type noPanicRule struct{}
+//
+//	func (noPanicRule) ID() string          { return "no-panic" }
+//	func (noPanicRule) Description() string { return "Tests fail with t.Fatal instead of panicking." }
+//	func (noPanicRule) Requirements() validator.Requirement { return validator.NeedsAST }
+//	func (noPanicRule) Check(file *validator.File) []validator.Issue { ... }
+//
+//	func init() { validator.Register(noPanicRule{}) }
+//
+// Besides Rule, a rule may implement Requirer, Documented, SeverityDefaulter
+// and Staged.
+type Rule interface {
+	// ID names the rule in the config, e.g. "get-prefix".
+	ID() string
+
+	// Description says in one line what the rule checks.
+	Description() string
+
+	// Check returns the issues found in file.
+	Check(file *File) []Issue
+}
+
+// Requirement says which inputs a rule reads from the File it checks.
+type Requirement uint8
+
+const (
+	// NeedsAST rules only walk File.AST. It is the default.
+	NeedsAST Requirement = 1 << iota
+	// NeedsTypes rules call File.TypesInfo.
+	NeedsTypes
+	// NeedsSource rules read the file contents or lines.
+	NeedsSource
+)
+
+// Requirer is implemented by rules needing more than the syntax tree. Drivers
+// use it to supply what the rule needs, e.g. the type information go vet
+// already computed.
+type Requirer interface {
+	Requirements() Requirement
+}
+
+// RuleRequirements returns the inputs r needs.
+func RuleRequirements(r Rule) Requirement {
+	if q, ok := r.(Requirer); ok && q.Requirements() != 0 {
+		return q.Requirements()
+	}
+	return NeedsAST
+}
+
+// Documented is implemented by rules with documentation beyond their
+// one-line description, such as examples of good and bad code.
+type Documented interface {
+	Docs() string
+}
+
+// RuleDocs returns the documentation of r, falling back to its description.
+func RuleDocs(r Rule) string {
+	if d, ok := r.(Documented); ok && d.Docs() != "" {
+		return d.Docs()
+	}
+	return r.Description()
+}
+
+// SeverityDefaulter is implemented by rules whose findings are not errors by
+// default, such as performance hints. The config can still override it.
+type SeverityDefaulter interface {
+	DefaultSeverity() Severity
+}
+
+// Stage is the lifecycle stage of a rule.
+type Stage string
+
+const (
+	// StageExperimental rules only run with Options.IncludeExperimental or
+	// when enabled by name in the config.
+	StageExperimental Stage = "experimental"
+	// StagePreview rules run, but their findings are warnings unless the
+	// config sets a severity, so they cannot break CI while being trialed.
+	StagePreview Stage = "preview"
+	// StageStable is the stage of rules that do not declare one.
+	StageStable Stage = "stable"
+	// StageDeprecated rules are going away: their findings are info notices
+	// that never fail a run, and they stop running after the removal date
+	// of their Deprecation.
+	StageDeprecated Stage = "deprecated"
+)
+
+// Staged is implemented by rules that are not stable yet, or no longer.
+type Staged interface {
+	Stage() Stage
+}
+
+// RuleStage returns the lifecycle stage of r.
+func RuleStage(r Rule) Stage {
+	if s, ok := r.(Staged); ok && s.Stage() != "" {
+		return s.Stage()
+	}
+	return StageStable
+}
+
+var (
+	// registry holds the rules every Validator runs, in order.
+	registry []Rule
+
+	// registered indexes registry by rule ID.
+	registered = make(map[string]Rule)
+)
+
+// Register adds r to the rules every Validator runs. It is meant to be
+// called from init functions and panics when the ID is already taken.
+func Register(r Rule) {
+	if _, ok := registered[r.ID()]; ok {
+		panic(fmt.Sprintf("validator: rule %q registered twice", r.ID()))
+	}
+	registered[r.ID()] = r
+	registry = append(registry, r)
+	if d, ok := r.(SeverityDefaulter); ok {
+		defaultSeverities[r.ID()] = d.DefaultSeverity()
+	}
+	if t, ok := r.(Tagged); ok {
+		if _, tagged := ruleTags[r.ID()]; !tagged {
+			ruleTags[r.ID()] = t.Tags()
+		}
+	}
+	if RuleStage(r) == StagePreview {
+		defaultSeverities[r.ID()] = min(defaultSeverity(r.ID()), SeverityWarning)
+	}
+	registerDeprecation(r)
+}
+
+// Rules returns the registered rules in the order they run.
+func Rules() []Rule {
+	return append([]Rule(nil), registry...)
+}
+
+// reportedBy maps the names some rules report findings under, besides their
+// ID, to the rule.
+var reportedBy = map[string]string{
+	"time-sleep":       "line-patterns",
+	"cfgplugin-return": "line-patterns",
+	"string-concat":    "line-patterns",
+	"error-string":     "line-patterns",
+	"const-grouping":   "const-name",
+	"initialism":       "mixed-caps",
+	"receiver-name":    "underscore",
+	"t-logf-args":      "t-log-args",
+}
+
+// RuleFor returns the registered rule reporting findings under name: the
+// rule with that ID, or the rule reporting under it besides its ID, such as
+// line-patterns for time-sleep.
+func RuleFor(name string) (Rule, bool) {
+	if r, ok := registered[name]; ok {
+		return r, true
+	}
+	r, ok := registered[reportedBy[name]]
+	return r, ok
+}
+
+// funcRule adapts a check function to the Rule interface.
+type funcRule struct {
+	id          string
+	description string
+	check       func(file *File, errs *[]Issue)
+
+	// testOnly restricts the rule to _test.go files.
+	testOnly bool
+	// optIn rules only run when enabled in the config.
+	optIn bool
+	// fixes marks rules that may rewrite the file; they run last.
+	fixes bool
+	// severity, when set, replaces error as the default severity.
+	severity Severity
+	// stage, when set, replaces stable as the lifecycle stage.
+	stage Stage
+	// requires, when set, replaces NeedsAST as the rule's inputs.
+	requires Requirement
+}
+
+func (r funcRule) ID() string          { return r.id }
+func (r funcRule) Description() string { return r.description }
+
+func (r funcRule) DefaultSeverity() Severity {
+	if r.severity == 0 {
+		return SeverityError
+	}
+	return r.severity
+}
+
+func (r funcRule) Stage() Stage              { return r.stage }
+func (r funcRule) Requirements() Requirement { return r.requires }
+
+func (r funcRule) Check(file *File) []Issue {
+	if r.testOnly && !file.IsTest() {
+		return nil
+	}
+	var errs []Issue
+	r.check(file, &errs)
+	return errs
+}
+
+// astRule wraps a check working on the parsed file.
+func astRule(id, description string, check func(path string, fs *token.FileSet, f *ast.File, errs *[]Issue)) funcRule {
+	return funcRule{id: id, description: description, check: func(file *File, errs *[]Issue) {
+		check(file.Path, file.Fset, file.AST, errs)
+	}}
+}
+
+// typedRule wraps a check working on the parsed file and its types.
+func typedRule(id, description string, check func(path string, fs *token.FileSet, f *ast.File, typesInfo *types.Info, errs *[]Issue)) funcRule {
+	return funcRule{id: id, description: description, requires: NeedsTypes, check: func(file *File, errs *[]Issue) {
+		check(file.Path, file.Fset, file.AST, file.TypesInfo(), errs)
+	}}
+}
+
+// pathRule wraps a check that parses the file itself. Its returned error
+// only repeats the issues, so it is dropped.
+func pathRule(id, description string, check func(path string, errs *[]Issue) error) funcRule {
+	return funcRule{id: id, description: description, requires: NeedsSource, check: func(file *File, errs *[]Issue) {
+		_ = check(file.Path, errs)
+	}}
+}
+
+// testRule marks r as applying to _test.go files only.
+func testRule(r funcRule) funcRule {
+	r.testOnly = true
+	return r
+}
+
+// withStage sets the lifecycle stage of r.
+func withStage(r funcRule, stage Stage) funcRule {
+	r.stage = stage
+	return r
+}
+
+// withSeverity sets the default severity of r's findings.
+func withSeverity(r funcRule, sev Severity) funcRule {
+	r.severity = sev
+	return r
+}
+
+func init() {
+	for _, r := range []funcRule{
+		astRule("var-mixed-caps", "Variables use mixedCaps.", validateMixedCaps),
+		{id: "acronym", description: "Known acronyms keep their casing inside identifiers.", check: func(file *File, errs *[]Issue) {
+			file.v.validateAcronyms(file.Path, file.Fset, file.AST, errs)
+		}},
+		{id: "const-name", description: "Constants use MixedCaps without a k prefix and related ones share a block.", check: func(file *File, errs *[]Issue) {
+			file.v.validateConstNames(file.Path, file.Fset, file.AST, errs)
+		}},
+		testRule(funcRule{id: "test-structure", description: "Test files hold a single table-driven test function.", check: func(file *File, errs *[]Issue) {
+			validateTestFileStructure(file.Path, file.AST, errs)
+		}}),
+		testRule(funcRule{id: "test-imports", description: "Selected test files only import allowed packages.", check: func(file *File, errs *[]Issue) {
+			file.v.validateTestImports(file.Path, file.Fset, file.AST, errs)
+		}}),
+		testRule(astRule("shared-test-helper", "Test helpers are not exported for use by other test files.", validateSharedTestHelpers)),
+		testRule(funcRule{id: "test-budget", description: "Declared waits fit within the test time budget.", check: func(file *File, errs *[]Issue) {
+			file.v.validateTestTimeBudget(file.Path, file.Fset, file.AST, errs)
+		}}),
+		testRule(astRule("unused-table-field", "Test table fields are read by the subtest loop.", validateUnusedTableFields)),
+		testRule(funcRule{id: "want-got", description: "Expected and actual values are named want and got.", check: func(file *File, errs *[]Issue) {
+			file.v.validateWantGotNames(file.Path, file.Fset, file.AST, errs)
+		}}),
+		testRule(typedRule("t-log-args", "t.Log gets one argument and t.Logf a format with arguments.", validateTestLogCalls)),
+		typedRule("format-verbs", "Printf-style format verbs match their arguments.", validateFormatVerbs),
+		{id: "import-visibility", description: "Internal and restricted packages are imported only where allowed.", check: func(file *File, errs *[]Issue) {
+			file.v.validateImportVisibility(file.Path, file.Fset, file.AST, errs)
+		}},
+		astRule("oc-list-key", "OC list keys are not built with fmt.Sprintf.", validateOCListKeys),
+		typedRule("mutex-defer", "Mutex locks are released with defer.", validateMutexDefer),
+		typedRule("waitgroup", "sync.WaitGroup is added to before its goroutines and not reused across loops.", validateWaitGroups),
+		astRule("unbuffered-send", "Goroutines do not send on unbuffered channels a select may abandon.", validateUnbufferedSends),
+		typedRule("error-string-match", "Errors are told apart with errors.Is/As or status codes, not their message.", validateErrorStringMatch),
+		astRule("grpc-status", "gRPC errors are inspected with the status and codes packages.", validateGRPCStatusCodes),
+		astRule("nil-map", "Maps are initialized before they are written to.", validateNilMapWrites),
+		typedRule("typed-nil", "Nil pointers are not returned as non-nil interfaces.", validateTypedNilReturns),
+		{id: "loop-capture", description: "Goroutines and parallel subtests do not capture shared loop variables.", check: func(file *File, errs *[]Issue) {
+			file.v.validateLoopCapture(file.Path, file.Fset, file.AST, errs)
+		}},
+		astRule("append-misuse", "append gets elements and its result goes back to the slice appended to.", validateAppendUsage),
+		withSeverity(typedRule("slice-prealloc", "Slices filled by a loop of known length are allocated up front.", validateSlicePrealloc), SeverityInfo),
+		withSeverity(astRule("regexp-hot-path", "Regexps are compiled once, not in loops or per test case.", validateRegexpHotPaths), SeverityWarning),
+		withSeverity(funcRule{id: "large-copy", description: "Large structs and arrays are not copied by parameters and range loops.", requires: NeedsTypes, check: func(file *File, errs *[]Issue) {
+			file.v.validateLargeCopies(file.Path, file.Fset, file.AST, file.TypesInfo(), errs)
+		}}, SeverityWarning),
+		withSeverity(astRule("context-field", "Structs do not store a context.Context.", validateContextFields), SeverityWarning),
+		withStage(astRule("context-cancel", "Cancel funcs of derived contexts are called.", validateContextCancel), StagePreview),
+		typedRule("otg-flow-name", "OTG flows are named once with constants and their metrics read by those names.", validateOTGFlowNames),
+		astRule("gribi-client", "gRIBI fluent clients set election ID and persistence and await with a timeout.", validateGRIBIClients),
+		{id: "gnoi-safety", description: "Disruptive gNOI calls are guarded or followed by a DUT health check.", check: func(file *File, errs *[]Issue) {
+			file.v.validateGNOISafety(file.Path, file.Fset, file.AST, errs)
+		}},
+		astRule("port-assumption", "Ports are looked up by testbed ID, not by position, count or fixed speed.", validatePortAssumptions),
+		{id: "binding-ref", description: "-binding and testbed flag defaults name files that exist.", check: func(file *File, errs *[]Issue) {
+			file.v.validateBindingRefs(file.Path, file.Fset, file.AST, file.Module(), errs)
+		}},
+		withSeverity(testRule(funcRule{id: "dual-stack", description: "Tests whose README claims dual-stack coverage use both address families.", optIn: true, check: func(file *File, errs *[]Issue) {
+			validateDualStack(file.Path, file.Fset, file.AST, errs)
+		}}), SeverityWarning),
+		testRule(funcRule{id: "build-constraint", description: "Tests carry only approved build constraints.", check: func(file *File, errs *[]Issue) {
+			file.v.validateBuildConstraints(file.Fset, file.AST, errs)
+		}}),
+		withSeverity(funcRule{id: "spelling", description: "Comments, READMEs and test log messages are free of common misspellings.", optIn: true, check: func(file *File, errs *[]Issue) {
+			file.v.validateSpelling(file.Path, file.Fset, file.AST, errs)
+		}}, SeverityInfo),
+		astRule("unicode", "Identifiers are ASCII and literals hold no invisible characters.", validateUnicode),
+		testRule(astRule("test-plan-name", "Feature tests are named after their directory or test plan.", validateTestPlanName)),
+		withSeverity(typedRule("device-name", "DUT, ATE and OTG variables are named dut, ate and otg.", validateDeviceNames), SeverityWarning),
+		astRule("doc-comment", "Exported functions have a doc comment starting with their name.", validateDocComments),
+		astRule("helper-assertion", "Helpers return errors instead of calling t.Error.", validateHelperAssertions),
+		{id: "get-prefix", description: "Functions do not use a Get prefix.", check: func(file *File, errs *[]Issue) {
+			file.v.validateGetPrefix(file.Path, file.Fset, file.AST, errs)
+		}},
+		testRule(astRule("test-helper", "Test helpers call t.Helper().", validateTestHelpers)),
+		testRule(astRule("test-helper-name", "Test helpers are unexported.", validateTestHelperNames)),
+		astRule("struct-param", "Struct parameters are used beyond passing a single field.", validateStructParams),
+		astRule("underscore", "Identifiers do not contain underscores.", validateUnderscores),
+		astRule("var-type-name", "Variables do not repeat their type in their name.", validateVarTypeNames),
+		testRule(astRule("must-prefix", "Functions failing the test are named mustXYZ.", validateMustUsage)),
+		astRule("nested-func-literal", "Function literals are not nested inside calls.", validateNestedAnonymousFuncs),
+		astRule("mixed-caps", "Functions, types and package variables use MixedCaps and cased initialisms.", checkMixedCaps),
+		{id: "line-patterns", description: "Lines avoid time.Sleep, string concatenation and capitalized error strings.", requires: NeedsSource, check: func(file *File, errs *[]Issue) {
+			*errs = append(*errs, file.v.scanFileForPatterns(file.Path)...)
+		}},
+		{id: "sleep-equivalents", description: "Waits do not get around the time.Sleep ban with time.After, time.Tick, unstopped tickers or configured wait helpers.", requires: NeedsTypes, check: func(file *File, errs *[]Issue) {
+			file.v.validateSleepEquivalents(file.Fset, file.AST, file.TypesInfo(), errs)
+		}},
+		{id: "commented-code", description: "Code is deleted rather than commented out.", requires: NeedsSource, check: func(file *File, errs *[]Issue) {
+			_ = file.v.validateCommentedCode(file.Path, errs)
+		}},
+		pathRule("unused-param", "Function parameters are used.", validateUnusedParameters),
+		pathRule("errors-new", "errors.New is not given a formatted string.", validateErrorsNewUsage),
+		pathRule("unused-field", "Struct fields are used.", validateUnusedStructFields),
+		pathRule("hardcoded-timeout", "Timeouts are not hardcoded.", validateHardcodedTimeout),
+		pathRule("gnmi-batch-mix", "gNMI batches do not mix Replace and Update.", validateMixedGNMIBatchUsage),
+		pathRule("subinterface-index", "Subinterface indexes come from attributes.", validateHardcodedSubinterfaceIndex),
+		pathRule("deviation-usage", "Code outside cfgplugins does not call the deviations package directly.", validateDeviationUsage),
+		pathRule("comment-name", "Function comments name the function.", validateFunctionCommentMatch),
+		pathRule("vendor-check", "dut.Vendor() is only checked inside if deviations.X() blocks.", validateVendorCheckInDeviation),
+		pathRule("log-instead-of-error", "Failures are reported with t.Error, not logged.", validateLogInsteadOfError),
+		pathRule("t-context", "t.Context() is not used, for Go 1.22 and 1.23 compatibility.", validateContextUsage),
+		pathRule("deviation-comment", "Deviation accessors named XxxUnsupported have a doc comment with a Tracked at: issue link.", validateDeviationComment),
+		pathRule("testing-t-param", "Configuration helpers take *testing.T first.", validateConfigurePoliciesSignature),
+		pathRule("magic-number", "Numbers are named constants.", validateMagicNumbers),
+		pathRule("float-equality", "Floats are not compared with == or !=.", validateFloatEquality),
+		withSeverity(funcRule{id: "injectable-clock", description: "Code reads the time through an injectable clock.", requires: NeedsTypes, check: func(file *File, errs *[]Issue) {
+			file.v.validateInjectableClock(file.Path, file.Fset, file.AST, file.TypesInfo(), errs)
+		}}, SeverityWarning),
+		pathRule("package-comment", "Comments above the package clause are package docs.", validatePackageClauseComments),
+		{id: "function-order", description: "Exported functions come before unexported helpers.", requires: NeedsSource, optIn: true, check: func(file *File, errs *[]Issue) {
+			_ = validateFunctionOrder(file.Path, errs)
+		}},
+		{id: "plugins", description: "Findings of the configured rule plugins.", requires: NeedsSource, check: func(file *File, errs *[]Issue) {
+			file.v.validatePlugins(file.Context(), file.Path, errs)
+		}},
+		{id: "wasm", description: "Findings of the configured WASM rules.", requires: NeedsAST | NeedsSource, check: func(file *File, errs *[]Issue) {
+			file.v.validateWASMRules(file.Context(), file.Path, file.Fset, file.AST, errs)
+		}},
+		{id: "custom", description: "Findings of the custom rules defined in the config.", requires: NeedsSource, check: func(file *File, errs *[]Issue) {
+			file.v.validateCustomRules(file, errs)
+		}},
+		pathRule("config-struct-literal", "Config structs passed to functions use keyed fields and set their required fields.", validateConfigStructLiterals),
+		{id: "unkeyed-literal", description: "Composite literals of imported structs use field names.", requires: NeedsSource, fixes: true, check: func(file *File, errs *[]Issue) {
+			_ = file.v.validateUnkeyedCompositeLiterals(file.Path, errs)
+		}},
+	} {
+		Register(r)
+	}
+}
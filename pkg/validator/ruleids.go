@@ -0,0 +1,124 @@
+package validator
+
+import "strings"
+
+// ruleIDs gives every rule a stable identifier that suppressions, baselines
+// and dashboards can refer to. IDs are never changed or reused; new rules
+// take the next free number. The numbers of time-sleep (FP009),
+// cfgplugin-return (FP018) and proto-bug-url (FP020) predate the table.
+var ruleIDs = map[string]string{
+	"parse-error":           "FP000",
+	"var-mixed-caps":        "FP001",
+	"acronym":               "FP002",
+	"test-structure":        "FP003",
+	"doc-comment":           "FP004",
+	"helper-assertion":      "FP005",
+	"get-prefix":            "FP006",
+	"test-helper":           "FP007",
+	"test-helper-name":      "FP008",
+	"time-sleep":            "FP009",
+	"struct-param":          "FP010",
+	"underscore":            "FP011",
+	"receiver-name":         "FP012",
+	"var-type-name":         "FP013",
+	"must-prefix":           "FP014",
+	"nested-func-literal":   "FP015",
+	"mixed-caps":            "FP016",
+	"initialism":            "FP017",
+	"cfgplugin-return":      "FP018",
+	"string-concat":         "FP019",
+	"proto-bug-url":         "FP020",
+	"error-string":          "FP021",
+	"commented-code":        "FP022",
+	"unused-param":          "FP023",
+	"errors-new":            "FP024",
+	"unused-field":          "FP025",
+	"hardcoded-timeout":     "FP026",
+	"gnmi-batch-mix":        "FP027",
+	"subinterface-index":    "FP028",
+	"deviation-usage":       "FP029",
+	"comment-name":          "FP030",
+	"vendor-check":          "FP031",
+	"log-instead-of-error":  "FP032",
+	"t-context":             "FP033",
+	"deviation-comment":     "FP034",
+	"testing-t-param":       "FP035",
+	"magic-number":          "FP036",
+	"float-equality":        "FP037",
+	"injectable-clock":      "FP038",
+	"package-comment":       "FP039",
+	"function-order":        "FP040",
+	"config-struct-literal": "FP041",
+	"unkeyed-literal":       "FP042",
+	"const-name":            "FP043",
+	"const-grouping":        "FP044",
+	"test-imports":          "FP045",
+	"shared-test-helper":    "FP046",
+	"test-budget":           "FP047",
+	"unused-table-field":    "FP048",
+	"want-got":              "FP049",
+	"t-log-args":            "FP050",
+	"t-logf-args":           "FP051",
+	"format-verbs":          "FP052",
+	"metadata-uuid":         "FP053",
+	"import-visibility":     "FP054",
+	"oc-list-key":           "FP055",
+	"mutex-defer":           "FP056",
+	"waitgroup":             "FP057",
+	"unbuffered-send":       "FP058",
+	"duplicate-func":        "FP059",
+	"import-alias":          "FP060",
+	"package-doc":           "FP061",
+	"error-string-match":    "FP062",
+	"grpc-status":           "FP063",
+	"nil-map":               "FP064",
+	"typed-nil":             "FP065",
+	"loop-capture":          "FP066",
+	"append-misuse":         "FP067",
+	"slice-prealloc":        "FP068",
+	"regexp-hot-path":       "FP069",
+	"large-copy":            "FP070",
+	"context-field":         "FP071",
+	"context-cancel":        "FP072",
+	"read-error":            "FP073",
+	"long-line":             "FP074",
+	"otg-flow-name":         "FP075",
+	"gribi-client":          "FP076",
+	"gnoi-safety":           "FP077",
+	"port-assumption":       "FP078",
+	"binding-ref":           "FP079",
+	"dual-stack":            "FP080",
+	"skipped-file":          "FP081",
+	"build-constraint":      "FP082",
+	"device-name":           "FP083",
+	"test-plan-name":        "FP084",
+	"unicode":               "FP085",
+	"spelling":              "FP086",
+	"sleep-equivalents":     "FP087",
+	"plugin":                "FP900",
+	"wasm":                  "FP901",
+}
+
+// ruleNames maps the IDs in ruleIDs back to rule names.
+var ruleNames = make(map[string]string)
+
+func init() {
+	for name, id := range ruleIDs {
+		ruleNames[id] = name
+	}
+}
+
+// RuleID returns the stable ID of the named rule, e.g. "FP009" for
+// "time-sleep", or "" for rules without one such as plugin rules.
+func RuleID(rule string) string {
+	return ruleIDs[rule]
+}
+
+// canonicalRule returns the rule name for ref, which is either a rule name
+// or a rule ID.
+func canonicalRule(ref string) string {
+	if name, ok := ruleNames[strings.ToUpper(ref)]; ok {
+		return name
+	}
+	return ref
+}
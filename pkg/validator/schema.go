@@ -0,0 +1,227 @@
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decodeConfig decodes the YAML config data into cfg against the schema
+// given by the Config type: unknown keys, such as a mistyped "enalbed",
+// values of the wrong type and rule names or IDs matching no rule are
+// errors naming the config line, instead of being silently ignored.
+func decodeConfig(data []byte, cfg *Config) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	err := dec.Decode(cfg)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	var problems []string
+	var typeErr *yaml.TypeError
+	switch {
+	case errors.As(err, &typeErr):
+		for _, msg := range typeErr.Errors {
+			problems = append(problems, schemaMessage(msg))
+		}
+	case err != nil:
+		return err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err == nil {
+		problems = append(problems, checkRuleRefs(&root, cfg)...)
+	}
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return errors.New(problems[0])
+	}
+	slices.SortStableFunc(problems, func(a, b string) int {
+		return problemLine(a) - problemLine(b)
+	})
+	return fmt.Errorf("%d problems:\n  %s", len(problems), strings.Join(problems, "\n  "))
+}
+
+// problemLine returns the config line a "line N: ..." problem refers to.
+func problemLine(problem string) int {
+	var line int
+	fmt.Sscanf(problem, "line %d:", &line)
+	return line
+}
+
+// unknownFieldRE matches the yaml.v3 error for a key the type lacks.
+var unknownFieldRE = regexp.MustCompile(`^(line \d+): field (\S+) not found in type (\S+)$`)
+
+// schemaMessage rewrites a yaml.v3 decoding error in the terms of the
+// config file, suggesting the closest key for unknown ones.
+func schemaMessage(msg string) string {
+	m := unknownFieldRE.FindStringSubmatch(msg)
+	if m == nil {
+		return msg
+	}
+	msg = fmt.Sprintf("%s: unknown key %q", m[1], m[2])
+	if key := closest(m[2], configKeys()[m[3]]); key != "" {
+		msg += fmt.Sprintf("; did you mean %q?", key)
+	}
+	return msg
+}
+
+var (
+	configKeysOnce sync.Once
+	configKeysMap  map[string][]string
+)
+
+// configKeys maps the name of each struct type in the config, as yaml.v3
+// reports it, e.g. "validator.RuleConfig", to its keys.
+func configKeys() map[string][]string {
+	configKeysOnce.Do(func() {
+		configKeysMap = make(map[string][]string)
+		var walk func(t reflect.Type)
+		walk = func(t reflect.Type) {
+			for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+				t = t.Elem()
+			}
+			if t.Kind() != reflect.Struct || configKeysMap[t.String()] != nil {
+				return
+			}
+			configKeysMap[t.String()] = []string{}
+			for i := range t.NumField() {
+				field := t.Field(i)
+				key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+				if key == "" || key == "-" {
+					continue
+				}
+				configKeysMap[t.String()] = append(configKeysMap[t.String()], key)
+				walk(field.Type)
+			}
+		}
+		walk(reflect.TypeFor[Config]())
+	})
+	return configKeysMap
+}
+
+// checkRuleRefs reports the rule names and IDs in the enable, disable,
+// rules and exempt sections of the config document root that match no
+// rule, with their line.
+func checkRuleRefs(root *yaml.Node, cfg *Config) []string {
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return nil
+	}
+	var problems []string
+	check := func(ref *yaml.Node) {
+		if ref.Kind != yaml.ScalarNode || cfg.knownRule(ref.Value) {
+			return
+		}
+		msg := fmt.Sprintf("line %d: unknown rule %q", ref.Line, ref.Value)
+		if name := closest(ref.Value, ruleNameList()); name != "" {
+			msg += fmt.Sprintf("; did you mean %q?", name)
+		}
+		problems = append(problems, msg)
+	}
+
+	doc := root.Content[0]
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key, value := doc.Content[i], doc.Content[i+1]
+		switch key.Value {
+		case "enable", "disable":
+			for _, ref := range value.Content {
+				check(ref)
+			}
+		case "rules":
+			for j := 0; j < len(value.Content); j += 2 {
+				check(value.Content[j])
+			}
+		case "exempt":
+			for _, entry := range value.Content {
+				for j := 0; j+1 < len(entry.Content); j += 2 {
+					if entry.Content[j].Value == "rules" {
+						for _, ref := range entry.Content[j+1].Content {
+							check(ref)
+						}
+					}
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// knownRule reports whether name, a rule name or ID, refers to a built-in
+// or registered rule, or to a plugin, WASM or custom rule of cfg.
+func (cfg *Config) knownRule(name string) bool {
+	name = canonicalRule(name)
+	if registered[name] != nil || RuleID(name) != "" {
+		return true
+	}
+	if plugin, ok := strings.CutPrefix(name, "plugin:"); ok {
+		return slices.ContainsFunc(cfg.Plugins, func(p PluginConfig) bool { return p.Name == plugin })
+	}
+	if wasm, ok := strings.CutPrefix(name, "wasm:"); ok {
+		return slices.ContainsFunc(cfg.WASMRules, func(w WASMRuleConfig) bool { return w.Name == wasm })
+	}
+	return slices.ContainsFunc(cfg.CustomRules, func(c CustomRuleConfig) bool { return c.Name == name })
+}
+
+// ruleNameList returns the names of the built-in and registered rules.
+func ruleNameList() []string {
+	var names []string
+	for name := range ruleIDs {
+		names = append(names, name)
+	}
+	for name := range registered {
+		if RuleID(name) == "" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// closest returns the candidate nearest to s when it is close enough to be
+// a likely typo, or "".
+func closest(s string, candidates []string) string {
+	best, bestDist := "", max(2, len(s)/3)+1
+	for _, c := range candidates {
+		if d := editDistance(strings.ToLower(s), strings.ToLower(c)); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the optimal string alignment distance between a and
+// b: the Levenshtein distance with swapped adjacent letters, the usual
+// typo, counted as one edit.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
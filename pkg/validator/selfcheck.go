@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"fmt"
+	"go/build/constraint"
+	"sort"
+)
+
+// CheckConfig reports the mistakes in cfg that LoadConfig lets through but
+// that silently change a run or only surface deep into it: rule names and
+// IDs that match no rule, unknown tags, and globs and build constraints
+// that do not compile. It returns nil for a sound config.
+func CheckConfig(cfg *Config) []error {
+	var errs []error
+
+	checkRules := func(field string, names []string) {
+		for _, name := range names {
+			if !cfg.knownRule(name) {
+				errs = append(errs, fmt.Errorf("%s: unknown rule %q", field, name))
+			}
+		}
+	}
+	checkGlobs := func(field string, patterns []string) {
+		for _, pattern := range patterns {
+			if err := checkGlob(pattern); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", field, err))
+			}
+		}
+	}
+
+	checkRules("enable", cfg.Enable)
+	checkRules("disable", cfg.Disable)
+	for i, e := range cfg.Exempt {
+		checkRules(fmt.Sprintf("exempt[%d].rules", i), e.Rules)
+		checkGlobs(fmt.Sprintf("exempt[%d].files", i), e.Files)
+	}
+	names := make([]string, 0, len(cfg.Rules))
+	for name := range cfg.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rule := cfg.Rules[name]
+		checkRules("rules", []string{name})
+		checkGlobs("rules."+name+".files", rule.Files)
+		checkGlobs("rules."+name+".ignore", rule.Ignore)
+		if rule.Escalate != nil {
+			checkGlobs("rules."+name+".escalate.files", rule.Escalate.Files)
+		}
+	}
+
+	if err := checkTags(cfg.OnlyTags); err != nil {
+		errs = append(errs, fmt.Errorf("onlyTags: %w", err))
+	}
+	if err := checkTags(cfg.SkipTags); err != nil {
+		errs = append(errs, fmt.Errorf("skipTags: %w", err))
+	}
+
+	checkGlobs("getPrefix.files", cfg.GetPrefix.Files)
+	checkGlobs("testImports.files", cfg.TestImports.Files)
+	checkGlobs("testImports.deny", cfg.TestImports.Deny)
+	checkGlobs("testImports.allow", cfg.TestImports.Allow)
+	for i, rule := range cfg.Visibility {
+		checkGlobs(fmt.Sprintf("visibility[%d].packages", i), rule.Packages)
+		checkGlobs(fmt.Sprintf("visibility[%d].files", i), rule.Files)
+	}
+	checkGlobs("bindings.external", cfg.Bindings.External)
+	for _, expr := range cfg.BuildConstraints.Allowed {
+		if _, err := constraint.Parse("//go:build " + expr); err != nil {
+			errs = append(errs, fmt.Errorf("buildConstraints.allowed: %q: %w", expr, err))
+		}
+	}
+	for _, c := range cfg.CustomRules {
+		checkGlobs("customRules."+c.Name+".imports", c.Imports)
+	}
+
+	if _, err := compileCustomRules(cfg.CustomRules); err != nil {
+		errs = append(errs, fmt.Errorf("customRules: %w", err))
+	}
+	return errs
+}
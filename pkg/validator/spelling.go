@@ -0,0 +1,132 @@
+package validator
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// bundledMisspellings is the spelling rule's list of common misspellings.
+//
+//go:embed spelling.txt
+var bundledMisspellings string
+
+// spellingWordRE matches the words checked by the spelling rule.
+var spellingWordRE = regexp.MustCompile(`[A-Za-z]+(?:'[A-Za-z]+)?`)
+
+// spellingSkipRE matches the parts of a text the spelling rule leaves out:
+// URLs and `code spans`.
+var spellingSkipRE = regexp.MustCompile("[a-z]+://\\S+|`[^`\n]*`")
+
+// testLogMethods are the testing.T methods whose messages are spell-checked.
+var testLogMethods = []string{"Log", "Logf", "Error", "Errorf", "Fatal", "Fatalf", "Skip", "Skipf"}
+
+// misspellings returns the misspellings the spelling rule reports, mapped to
+// their corrections: the bundled list plus spelling.corrections, minus
+// spelling.words.
+func (v *Validator) misspellings() map[string]string {
+	if v.spelling != nil {
+		return v.spelling
+	}
+	v.spelling = make(map[string]string)
+	for _, line := range strings.Split(bundledMisspellings, "\n") {
+		if word, correction, ok := strings.Cut(line, " "); ok && !strings.HasPrefix(line, "#") {
+			v.spelling[word] = correction
+		}
+	}
+	for word, correction := range v.cfg.Spelling.Corrections {
+		v.spelling[strings.ToLower(word)] = correction
+	}
+	for _, word := range v.cfg.Spelling.Words {
+		delete(v.spelling, strings.ToLower(word))
+	}
+	return v.spelling
+}
+
+// validateSpelling reports likely misspellings in the comments of f, in the
+// messages of its t.Log, t.Error, t.Fatal and t.Skip calls, and, from the
+// alphabetically first .go file of a directory, in its README.md.
+func (v *Validator) validateSpelling(path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CommentGroup:
+			for _, c := range n.List {
+				v.checkSpelling(c.Text, func(offset int) token.Position {
+					return fs.Position(c.Pos() + token.Pos(offset))
+				}, errs)
+			}
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok || !containsString(testLogMethods, sel.Sel.Name) {
+				return true
+			}
+			if _, ok := sel.X.(*ast.Ident); !ok {
+				return true
+			}
+			for _, arg := range n.Args {
+				if lit, ok := arg.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					v.checkSpelling(lit.Value, func(offset int) token.Position {
+						return fs.Position(lit.Pos() + token.Pos(offset))
+					}, errs)
+				}
+			}
+		}
+		return true
+	})
+
+	dir := filepath.Dir(path)
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.go")); len(files) == 0 || filepath.Clean(files[0]) != filepath.Clean(path) {
+		return
+	}
+	readme := filepath.Join(dir, "README.md")
+	data, err := os.ReadFile(readme)
+	if err != nil {
+		return
+	}
+	text := string(data)
+	v.checkSpelling(text, func(offset int) token.Position {
+		line := strings.Count(text[:offset], "\n") + 1
+		col := offset - strings.LastIndex(text[:offset], "\n")
+		return token.Position{Filename: readme, Offset: offset, Line: line, Column: col}
+	}, errs)
+}
+
+// checkSpelling reports the misspelled words of text, located by pos from
+// their byte offset in text.
+func (v *Validator) checkSpelling(text string, pos func(offset int) token.Position, errs *[]Issue) {
+	misspellings := v.misspellings()
+	skipped := spellingSkipRE.FindAllStringIndex(text, -1)
+	for _, loc := range spellingWordRE.FindAllStringIndex(text, -1) {
+		word := text[loc[0]:loc[1]]
+		correction, ok := misspellings[strings.ToLower(word)]
+		if !ok {
+			continue
+		}
+		inSkipped := false
+		for _, s := range skipped {
+			inSkipped = inSkipped || (loc[0] >= s[0] && loc[1] <= s[1])
+		}
+		if inSkipped {
+			continue
+		}
+		suggest(errs, "spelling", pos(loc[0]), fmt.Sprintf("use %q", matchCase(word, correction)), "%q looks misspelled", word)
+	}
+}
+
+// matchCase gives correction the case of word: "Recieve" becomes "Receive"
+// and "RECIEVE" becomes "RECEIVE".
+func matchCase(word, correction string) string {
+	switch {
+	case len(word) > 1 && strings.ToUpper(word) == word:
+		return strings.ToUpper(correction)
+	case unicode.IsUpper(rune(word[0])):
+		return strings.ToUpper(correction[:1]) + correction[1:]
+	}
+	return correction
+}
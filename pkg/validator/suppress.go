@@ -0,0 +1,154 @@
+package validator
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ignoreDirective suppresses findings of the listed rules, e.g.
+//
+//	//fpvalidator:ignore magic-number,float-equality TODO(b/123): reason
+//
+// A directive alone on its line applies to the next line; after code it
+// applies to its own line. Text files with '#' comments use
+// "#fpvalidator:ignore".
+const ignoreDirective = "fpvalidator:ignore"
+
+// fileIgnoreDirective suppresses findings of the listed rules in the whole
+// file. In Go files it must come before the package clause, e.g. next to
+// the "Code generated" header:
+//
+//	//fpvalidator:file-ignore FP003,doc-comment generated bindings
+const fileIgnoreDirective = "fpvalidator:file-ignore"
+
+// fileSuppressions caches the suppressed rules of each file by line. Line 0
+// holds the rules suppressed in the whole file.
+var fileSuppressions = make(map[string]map[int][]string)
+
+// suppressions returns the rules suppressed on each line of path. The file
+// is streamed line by line rather than read whole.
+func suppressions(path string) map[int][]string {
+	if lines, ok := fileSuppressions[path]; ok {
+		return lines
+	}
+
+	lines := make(map[int][]string)
+	if f, err := os.Open(path); err == nil {
+		r := bufio.NewReader(f)
+		header := true
+		for i := 0; ; i++ {
+			line, _, err := readLine(r, defaultMaxLineSize)
+			if err != nil && line == "" {
+				break
+			}
+			if strings.HasSuffix(path, ".go") && strings.HasPrefix(line, "package ") {
+				header = false
+			}
+			if _, rules, ok := parseDirective(line, fileIgnoreDirective); ok && header {
+				for _, rule := range rules {
+					lines[0] = append(lines[0], canonicalRule(rule))
+				}
+				continue
+			}
+
+			code, rules, ok := parseIgnoreDirective(line)
+			if !ok {
+				continue
+			}
+			target := i + 1
+			if strings.TrimSpace(code) == "" {
+				target++
+			}
+			for _, rule := range rules {
+				lines[target] = append(lines[target], canonicalRule(rule))
+			}
+		}
+		f.Close()
+	}
+	fileSuppressions[path] = lines
+	return lines
+}
+
+// parseIgnoreDirective splits line into the code before an ignore directive
+// and the rules the directive lists.
+func parseIgnoreDirective(line string) (code string, rules []string, ok bool) {
+	return parseDirective(line, ignoreDirective)
+}
+
+// parseDirective splits line into the code before directive and the rules
+// the directive lists.
+func parseDirective(line, directive string) (code string, rules []string, ok bool) {
+	for _, marker := range []string{"//" + directive, "#" + directive} {
+		i := strings.Index(line, marker)
+		if i < 0 {
+			continue
+		}
+		fields := strings.Fields(line[i+len(marker):])
+		if len(fields) == 0 {
+			return "", nil, false
+		}
+		return line[:i], strings.Split(fields[0], ","), true
+	}
+	return "", nil, false
+}
+
+// dropSuppressed removes the findings suppressed by ignore directives.
+func dropSuppressed(diags []Issue) []Issue {
+	out := diags[:0]
+	for _, d := range diags {
+		lines := suppressions(d.Pos.Filename)
+		if containsString(lines[0], d.Rule) {
+			continue
+		}
+		if d.Pos.Line == 0 || !containsString(lines[d.Pos.Line], d.Rule) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// InsertSuppressions adds an ignore directive for rule above each of the
+// given 1-based lines of path, extending a directive already there. The
+// directive ends with reason, e.g. a TODO naming the tracking bug.
+func InsertSuppressions(path string, lines []int, rule, reason string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	comment := "//"
+	if ext := filepath.Ext(path); ext == ".textproto" || ext == ".yaml" {
+		comment = "#"
+	}
+
+	src := strings.Split(string(data), "\n")
+	// Insert bottom-up so earlier line numbers stay valid.
+	sort.Sort(sort.Reverse(sort.IntSlice(lines)))
+	for _, line := range lines {
+		i := line - 1
+		if i >= len(src) {
+			continue
+		}
+		if i > 0 {
+			if code, rules, ok := parseIgnoreDirective(src[i-1]); ok && strings.TrimSpace(code) == "" {
+				if !containsString(rules, rule) {
+					old := strings.Join(rules, ",")
+					src[i-1] = strings.Replace(src[i-1], old, old+","+rule, 1)
+				}
+				continue
+			}
+		}
+		indent := src[i][:len(src[i])-len(strings.TrimLeft(src[i], " \t"))]
+		directive := indent + comment + ignoreDirective + " " + rule + " " + reason
+		src = append(src[:i], append([]string{directive}, src[i:]...)...)
+	}
+
+	return os.WriteFile(path, []byte(strings.Join(src, "\n")), info.Mode())
+}
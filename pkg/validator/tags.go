@@ -0,0 +1,151 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// ruleTags puts every rule into one or more categories, so a run can be
+// limited to, say, the gNMI and ondatra checks of a plugin repository.
+// Registered rules outside the table may declare theirs with Tagged.
+var ruleTags = map[string][]string{
+	"var-mixed-caps":        {"naming"},
+	"acronym":               {"naming"},
+	"test-structure":        {"testing"},
+	"doc-comment":           {"style"},
+	"helper-assertion":      {"testing"},
+	"get-prefix":            {"naming"},
+	"test-helper":           {"testing"},
+	"test-helper-name":      {"testing", "naming"},
+	"time-sleep":            {"testing", "gnmi"},
+	"struct-param":          {"style"},
+	"underscore":            {"naming"},
+	"receiver-name":         {"naming"},
+	"var-type-name":         {"naming"},
+	"must-prefix":           {"testing", "naming"},
+	"nested-func-literal":   {"style"},
+	"mixed-caps":            {"naming"},
+	"initialism":            {"naming"},
+	"cfgplugin-return":      {"gnmi"},
+	"string-concat":         {"style"},
+	"proto-bug-url":         {"proto"},
+	"error-string":          {"errors", "style"},
+	"commented-code":        {"style"},
+	"unused-param":          {"style"},
+	"errors-new":            {"errors"},
+	"unused-field":          {"style"},
+	"hardcoded-timeout":     {"testing"},
+	"gnmi-batch-mix":        {"gnmi"},
+	"subinterface-index":    {"gnmi"},
+	"deviation-usage":       {"gnmi"},
+	"comment-name":          {"style"},
+	"vendor-check":          {"gnmi"},
+	"log-instead-of-error":  {"testing"},
+	"t-context":             {"testing"},
+	"deviation-comment":     {"gnmi", "style"},
+	"testing-t-param":       {"testing"},
+	"magic-number":          {"style"},
+	"float-equality":        {"bugs"},
+	"injectable-clock":      {"testing"},
+	"package-comment":       {"style"},
+	"function-order":        {"style"},
+	"config-struct-literal": {"gnmi"},
+	"unkeyed-literal":       {"style"},
+	"const-name":            {"naming"},
+	"const-grouping":        {"style"},
+	"test-imports":          {"testing"},
+	"shared-test-helper":    {"testing"},
+	"test-budget":           {"testing"},
+	"unused-table-field":    {"testing"},
+	"want-got":              {"testing", "naming"},
+	"t-log-args":            {"testing"},
+	"t-logf-args":           {"testing"},
+	"format-verbs":          {"bugs"},
+	"metadata-uuid":         {"proto"},
+	"import-visibility":     {"style"},
+	"oc-list-key":           {"gnmi"},
+	"mutex-defer":           {"concurrency"},
+	"waitgroup":             {"concurrency"},
+	"unbuffered-send":       {"concurrency"},
+	"duplicate-func":        {"style"},
+	"import-alias":          {"style"},
+	"package-doc":           {"style"},
+	"error-string-match":    {"errors"},
+	"grpc-status":           {"errors", "gnmi"},
+	"nil-map":               {"bugs"},
+	"typed-nil":             {"errors", "bugs"},
+	"loop-capture":          {"concurrency", "bugs"},
+	"append-misuse":         {"bugs"},
+	"slice-prealloc":        {"performance"},
+	"regexp-hot-path":       {"performance"},
+	"large-copy":            {"performance"},
+	"context-field":         {"concurrency", "style"},
+	"context-cancel":        {"concurrency", "bugs"},
+	"otg-flow-name":         {"gnmi", "testing"},
+	"gribi-client":          {"gnmi", "testing"},
+	"gnoi-safety":           {"gnmi", "testing"},
+	"port-assumption":       {"gnmi", "testing"},
+	"binding-ref":           {"testing"},
+	"dual-stack":            {"testing"},
+	"build-constraint":      {"testing"},
+	"device-name":           {"gnmi", "naming"},
+	"test-plan-name":        {"testing", "naming"},
+	"unicode":               {"bugs", "naming"},
+	"spelling":              {"style"},
+	"sleep-equivalents":     {"testing", "gnmi"},
+}
+
+// Tagged is implemented by registered rules declaring their categories,
+// e.g. "gnmi" or "testing".
+type Tagged interface {
+	Tags() []string
+}
+
+// RuleTags returns the categories of the named rule.
+func RuleTags(rule string) []string {
+	return ruleTags[canonicalRule(rule)]
+}
+
+// Tags returns every known category in alphabetical order.
+func Tags() []string {
+	var tags []string
+	for _, ruleTags := range ruleTags {
+		for _, tag := range ruleTags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// checkTags returns an error naming the first of tags no rule carries.
+func checkTags(tags []string) error {
+	known := Tags()
+	for _, tag := range tags {
+		if !slices.Contains(known, tag) {
+			return fmt.Errorf("unknown rule tag %q, want one of %v", tag, known)
+		}
+	}
+	return nil
+}
+
+// tagSelected reports whether the findings of rule pass the OnlyTags and
+// SkipTags options. Findings of rules without tags, such as parse errors,
+// skipped files and the plugin, WASM and custom rules, always pass: tags
+// cannot say whether they matter to the run.
+func (v *Validator) tagSelected(rule string) bool {
+	tags := RuleTags(rule)
+	if len(tags) == 0 {
+		return true
+	}
+	if slices.ContainsFunc(tags, func(t string) bool { return slices.Contains(v.opts.SkipTags, t) }) {
+		return false
+	}
+	if len(v.opts.OnlyTags) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(tags, func(t string) bool { return slices.Contains(v.opts.OnlyTags, t) })
+}
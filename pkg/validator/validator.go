@@ -0,0 +1,436 @@
+// Package validator checks featureprofiles code against the repository
+// conventions: Go style and test structure rules, .proto bug references and
+// test metadata. The validator command is a thin wrapper around it; other
+// tools and CI bots can embed it instead of running the binary:
+//
+//	v, err := validator.New(validator.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	defer v.Close()
+//	issues, err := v.Validate("feature/bgp", "internal/cfgplugins")
+package validator
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"hash/fnv"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+)
+
+// Options configures a Validator. The zero value runs the default rules.
+type Options struct {
+	// Config holds settings read with LoadConfig; nil uses the defaults.
+	Config *Config
+
+	// ClockInTests also flags time.Now()/time.Since() inside _test.go files.
+	ClockInTests bool
+
+	// Fix rewrites files with the automatic fixes offered by rules.
+	Fix bool
+
+	// KeepDuplicates reports every finding of rules giving the same guidance
+	// at the same position instead of folding them into one Issue.
+	KeepDuplicates bool
+
+	// IncludeExperimental also runs the rules in StageExperimental.
+	IncludeExperimental bool
+
+	// OnlyTags, when set, limits the findings to rules carrying one of these
+	// tags, e.g. "gnmi"; SkipTags drops the rules carrying one of them.
+	OnlyTags []string
+	SkipTags []string
+
+	// Verbose, when set, receives notes about files the rules skip, such as
+	// binary and minified files.
+	Verbose io.Writer
+
+	// MaxFileSize and MaxMemory, when set, replace the config's maxFileSize
+	// and maxMemory.
+	MaxFileSize int64
+	MaxMemory   int64
+
+	// Shard, from 1 to Shards, selects the part of the files this run checks
+	// when validation is split across Shards parallel jobs. Files are
+	// assigned by directory, so package checks still see whole packages;
+	// the checks spanning a root, such as metadata UUIDs, run in shard 1.
+	Shard  int
+	Shards int
+
+	// Include, when set, limits the walk to the files matching one of its
+	// globs, and the walk skips the files matching one of Exclude, e.g.
+	// "internal/generated/**". Like config globs, they match anywhere in a
+	// path.
+	Include []string
+	Exclude []string
+}
+
+// Validator runs the rules over files and directories.
+type Validator struct {
+	opts Options
+	cfg  Config
+
+	optIn      map[string]bool
+	disabled   map[string]bool
+	acronyms   []string
+	rules      map[string]RuleConfig
+	testBudget time.Duration
+
+	plugins     []*rpcPlugin
+	wasmRuntime wazero.Runtime
+	wasmRules   []*wasmRule
+	customRules []customRule
+
+	// unscanned caches why files are left out of the line-based rules; an
+	// empty reason means they are scanned.
+	unscanned map[string]string
+
+	// checked counts the .go files the rules ran over.
+	checked int
+
+	// spelling caches the misspellings the spelling rule reports.
+	spelling map[string]string
+
+	// uuids records where each metadata uuid was first seen in the current
+	// Validate call, so duplicates across roots are found too.
+	uuids map[string]token.Position
+}
+
+// defaultRuleConfigs scopes rules that only make sense for some files.
+// Config entries replace them rule by rule.
+var defaultRuleConfigs = map[string]RuleConfig{
+	"cfgplugin-return": {Files: []string{"**/cfgplugins/**", "**/cfgplugins.go"}},
+}
+
+// validateMu serializes Validate calls, since rules share package-level
+// caches such as compiled globs and the type checker's importer.
+var validateMu sync.Mutex
+
+// New returns a Validator for opts and starts the plugins and WASM rules
+// listed in its config. Call Close to stop them.
+func New(opts Options) (*Validator, error) {
+	v := &Validator{
+		opts:     opts,
+		optIn:    make(map[string]bool),
+		disabled: make(map[string]bool),
+		acronyms: defaultAcronyms,
+		rules:    make(map[string]RuleConfig),
+
+		unscanned: make(map[string]string),
+	}
+	for name, rule := range defaultRuleConfigs {
+		v.rules[name] = rule
+	}
+	if opts.Config != nil {
+		v.opts.OnlyTags = slices.Concat(opts.Config.OnlyTags, opts.OnlyTags)
+		v.opts.SkipTags = slices.Concat(opts.Config.SkipTags, opts.SkipTags)
+	}
+	if err := checkTags(append(v.opts.OnlyTags, v.opts.SkipTags...)); err != nil {
+		return nil, err
+	}
+	if opts.Shards > 0 && (opts.Shard < 1 || opts.Shard > opts.Shards) {
+		return nil, fmt.Errorf("shard %d/%d is out of range, want 1 to %d", opts.Shard, opts.Shards, opts.Shards)
+	}
+	for _, pattern := range slices.Concat(opts.Include, opts.Exclude) {
+		if err := checkGlob(pattern); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Config == nil {
+		v.setMemoryLimit()
+		return v, nil
+	}
+
+	cfg := opts.Config
+	v.cfg = *cfg
+	v.acronyms = append(append([]string(nil), defaultAcronyms...), cfg.Acronyms...)
+	v.testBudget, _ = time.ParseDuration(cfg.TestBudget)
+	// Rules may be referred to by name or by ID.
+	for _, name := range cfg.Enable {
+		v.optIn[canonicalRule(name)] = true
+	}
+	for _, name := range cfg.Disable {
+		v.disabled[canonicalRule(name)] = true
+	}
+	for name, rule := range cfg.Rules {
+		name = canonicalRule(name)
+		// An entry only changing the severity keeps the default scope.
+		if len(rule.Files) == 0 && len(rule.Ignore) == 0 {
+			rule.Files = defaultRuleConfigs[name].Files
+		}
+		v.rules[name] = rule
+	}
+
+	v.setMemoryLimit()
+
+	customRules, err := compileCustomRules(cfg.CustomRules)
+	if err != nil {
+		return nil, err
+	}
+	v.customRules = customRules
+
+	if err := v.startPlugins(cfg.Plugins); err != nil {
+		return nil, err
+	}
+	if err := v.loadWASMRules(context.Background(), cfg.WASMRules); err != nil {
+		v.Close()
+		return nil, err
+	}
+	return v, nil
+}
+
+// Close stops the plugins and releases the WASM rules. It returns the
+// errors of plugins that did not exit cleanly.
+func (v *Validator) Close() error {
+	v.closeWASMRules(context.Background())
+	return v.stopPlugins()
+}
+
+// Forget drops what v and the rules have cached about the files on disk:
+// skipped files, suppression directives, modules and workspaces, and git
+// history, and getPrefix interfaces that failed to load. Long-lived
+// Validators, such as the one of "validator daemon", call it before each
+// run so edits made since are seen. Type information of imported packages
+// is kept, since it is what keeps repeated runs fast.
+func (v *Validator) Forget() {
+	validateMu.Lock()
+	defer validateMu.Unlock()
+
+	clear(v.unscanned)
+	clear(modules)
+	clear(workspaces)
+	clear(gitTopLevels)
+	clear(gitAddedDates)
+	clear(fileSuppressions)
+	maps.DeleteFunc(getPrefixInterfaces, func(_ [2]string, lookup interfaceLookup) bool {
+		return lookup.err != nil
+	})
+}
+
+// FilesChecked returns the number of .go files the rules ran over since New,
+// leaving out skipped files and files of other shards.
+func (v *Validator) FilesChecked() int {
+	validateMu.Lock()
+	defer validateMu.Unlock()
+	return v.checked
+}
+
+// Validate runs every rule over paths, each a directory walked recursively
+// or a single .go file, and returns the issues found.
+func (v *Validator) Validate(paths ...string) ([]Issue, error) {
+	return v.ValidateContext(context.Background(), paths...)
+}
+
+// ValidateContext is like Validate but stops once ctx is done, e.g. on
+// SIGINT or a CI timeout. It then returns the issues of the files already
+// checked together with ctx.Err(), so callers can flush partial results.
+func (v *Validator) ValidateContext(ctx context.Context, paths ...string) ([]Issue, error) {
+	validateMu.Lock()
+	defer validateMu.Unlock()
+
+	v.uuids = make(map[string]token.Position)
+	var issues []Issue
+	for _, path := range paths {
+		errs, err := v.validateRoot(ctx, path)
+		if ctx.Err() != nil {
+			return append(issues, errs...), ctx.Err()
+		}
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, errs...)
+	}
+	return issues, nil
+}
+
+// validateRoot runs every check against a single directory or .go file.
+// When ctx is done it returns the issues found so far.
+func (v *Validator) validateRoot(ctx context.Context, root string) ([]Issue, error) {
+	var errs []Issue
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %w", err)
+	}
+
+	if v.opts.Shards <= 1 || v.opts.Shard == 1 {
+		// Rule 20: check .proto files for full URL + bug ID
+		errs = append(errs, v.checkProtoFiles(ctx, root)...)
+		errs = append(errs, checkMetadataUUIDs(root, v.uuids)...)
+	}
+
+	var goFiles []string
+	if info.IsDir() {
+		_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if ctx.Err() != nil {
+				return filepath.SkipAll
+			}
+			if err == nil && info.IsDir() {
+				v.noteModule(path)
+			}
+			if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") || !v.inShard(root, path) || !v.selected(path) {
+				return nil
+			}
+			// Files over the size limit or memory budget are left out of the
+			// package checks too.
+			if v.skipFile(path, &errs) {
+				return nil
+			}
+			v.validateGoFile(ctx, path, &errs)
+			goFiles = append(goFiles, path)
+			return nil
+		})
+	} else {
+		if !strings.HasSuffix(root, ".go") {
+			return nil, fmt.Errorf("provided file is not a .go file")
+		}
+		if v.inShard(filepath.Dir(root), root) && v.selected(root) && !v.skipFile(root, &errs) {
+			v.validateGoFile(ctx, root, &errs)
+			goFiles = append(goFiles, root)
+		}
+	}
+	v.checked += len(goFiles)
+	if ctx.Err() != nil {
+		return v.finishIssues(errs), ctx.Err()
+	}
+
+	// Checks spanning the files of a package run once every file is done.
+	errs = append(errs, validatePackages(goFiles)...)
+
+	return v.finishIssues(errs), nil
+}
+
+// selected reports whether the file at path passes the Include and Exclude
+// options.
+func (v *Validator) selected(path string) bool {
+	if len(v.opts.Include) > 0 && !matchAnyGlob(v.opts.Include, path) {
+		return false
+	}
+	return !matchAnyGlob(v.opts.Exclude, path)
+}
+
+// inShard reports whether the file at path, under root, is checked by this
+// shard. Its directory relative to root is hashed, so every job of a
+// sharded run agrees on the split wherever the checkout lives.
+func (v *Validator) inShard(root, path string) bool {
+	if v.opts.Shards <= 1 {
+		return true
+	}
+	dir, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil {
+		dir = filepath.Dir(path)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(filepath.ToSlash(dir)))
+	return int(h.Sum32()%uint32(v.opts.Shards)) == v.opts.Shard-1
+}
+
+// validateGoFile runs the registered rules against a single Go file. Rules
+// that may rewrite the file run after the others, and none start once ctx
+// is done, so a cancelled run never leaves a file half fixed.
+func (v *Validator) validateGoFile(ctx context.Context, path string, errs *[]Issue) {
+	fs := token.NewFileSet()
+	f, err := parser.ParseFile(fs, path, nil, parser.ParseComments)
+	if err != nil {
+		report(errs, "parse-error", token.Position{Filename: path}, "failed parsing")
+		return
+	}
+
+	file := &File{Path: path, Fset: fs, AST: f, v: v, ctx: ctx}
+	for _, fixing := range []bool{false, true} {
+		for _, r := range registry {
+			if ctx.Err() != nil {
+				return
+			}
+			fr, builtin := r.(funcRule)
+			if builtin && fr.fixes != fixing || !builtin && fixing {
+				continue
+			}
+			if v.runs(r) {
+				*errs = append(*errs, r.Check(file)...)
+			}
+		}
+	}
+}
+
+// runs reports whether the config leaves r switched on.
+func (v *Validator) runs(r Rule) bool {
+	if v.disabled[r.ID()] {
+		return false
+	}
+	// Rules reporting under several names are filtered by finding instead.
+	if _, tagged := ruleTags[r.ID()]; tagged && !v.tagSelected(r.ID()) {
+		return false
+	}
+	if RuleStage(r) == StageExperimental && !v.opts.IncludeExperimental && !v.optIn[r.ID()] {
+		return false
+	}
+	if retired(r.ID()) {
+		return false
+	}
+	fr, builtin := r.(funcRule)
+	return !builtin || !fr.optIn || v.optIn[r.ID()]
+}
+
+// CheckFile runs the single rule r against f, parsed from path into fset,
+// and returns its issues with the config's scoping, severities and
+// suppressions applied. It lets drivers such as go/analysis run the rules
+// one at a time.
+func (v *Validator) CheckFile(r Rule, path string, fset *token.FileSet, f *ast.File) []Issue {
+	validateMu.Lock()
+	defer validateMu.Unlock()
+
+	if !v.runs(r) {
+		return nil
+	}
+	errs := r.Check(&File{Path: path, Fset: fset, AST: f, v: v})
+	return v.finishIssues(errs)
+}
+
+// CheckTypedFile is like CheckFile for drivers that already type-checked
+// the package: rules declaring NeedsTypes use info instead of checking f on
+// their own.
+func (v *Validator) CheckTypedFile(r Rule, path string, fset *token.FileSet, f *ast.File, info *types.Info) []Issue {
+	validateMu.Lock()
+	defer validateMu.Unlock()
+
+	if !v.runs(r) {
+		return nil
+	}
+	errs := r.Check(&File{Path: path, Fset: fset, AST: f, v: v, info: info})
+	return v.finishIssues(errs)
+}
+
+// CheckPackage runs the checks spanning the files of a package, such as
+// duplicate functions or a missing TestMain, against the given files.
+func (v *Validator) CheckPackage(paths []string) []Issue {
+	validateMu.Lock()
+	defer validateMu.Unlock()
+
+	return v.finishIssues(validatePackages(paths))
+}
+
+// finishIssues applies the config and the inline suppressions to errs,
+// folds duplicates and sorts what is left.
+func (v *Validator) finishIssues(errs []Issue) []Issue {
+	errs = v.applyRuleConfigs(errs)
+	errs = dropSuppressed(errs)
+	if !v.opts.KeepDuplicates {
+		errs = dedupeIssues(errs)
+	}
+	SortIssues(errs)
+	return errs
+}
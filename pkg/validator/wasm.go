@@ -0,0 +1,313 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// WASM custom rules run inside a wazero sandbox without filesystem or
+// network access. A rule module must export:
+//
+//	memory
+//	alloc(size i32) i32            reserves size bytes for the host to fill
+//	check(ptr i32, len i32) i64    inspects the input, returns ptr<<32 | len
+//
+// The input is the JSON encoding of wasmInput and the output the JSON
+// encoding of []wasmFinding. Modules built as WASI reactors have their
+// _initialize export run once after instantiation. Rules configured with
+// input: ast also receive the parsed file as a tree of wasmNode, so they need
+// no Go parser of their own.
+//
+// Each check call is limited by the rule's timeout, and each module's
+// memory by wasmMemoryLimitPages. A module that times out or exits is
+// instantiated afresh for the next file.
+
+const (
+	// wasmMemoryLimitPages caps the memory of every module at 256 MiB.
+	wasmMemoryLimitPages = 4096
+
+	// wasmDefaultTimeout bounds a check call when the rule sets no timeout.
+	wasmDefaultTimeout = 10 * time.Second
+)
+
+// wasmInput is the document passed to a WASM rule for every file.
+type wasmInput struct {
+	Path   string    `json:"path"`
+	Source string    `json:"source"`
+	AST    *wasmNode `json:"ast,omitempty"`
+}
+
+// wasmNode is the JSON form of a go/ast node.
+type wasmNode struct {
+	// Kind is the go/ast type name, e.g. "FuncDecl" or "CallExpr".
+	Kind string `json:"kind"`
+	// Name is set on identifiers.
+	Name string `json:"name,omitempty"`
+	// Value holds the text of literals and comments, the operator of
+	// expressions and statements, and the keyword of declarations.
+	Value    string      `json:"value,omitempty"`
+	Line     int         `json:"line"`
+	Col      int         `json:"col"`
+	EndLine  int         `json:"endLine"`
+	Children []*wasmNode `json:"children,omitempty"`
+}
+
+// wasmFinding is a single violation reported by a WASM rule.
+type wasmFinding struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+
+	// Suggestion optionally says how to resolve the finding.
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// wasmRule is an instantiated WASM rule module.
+type wasmRule struct {
+	name    string
+	withAST bool
+	timeout time.Duration
+
+	// rt, compiled and cfg instantiate mod again after it is closed.
+	rt       wazero.Runtime
+	compiled wazero.CompiledModule
+	cfg      wazero.ModuleConfig
+
+	mod   api.Module
+	alloc api.Function
+	check api.Function
+
+	// broken is set when mod could not be instantiated again; the rule then
+	// reports nothing for the rest of the run.
+	broken bool
+}
+
+// loadWASMRules compiles and instantiates every WASM rule in the config.
+func (v *Validator) loadWASMRules(ctx context.Context, cfgs []WASMRuleConfig) error {
+	if len(cfgs) == 0 {
+		return nil
+	}
+
+	// Closing on context done lets timeouts interrupt runaway modules.
+	rtCfg := wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(wasmMemoryLimitPages)
+	v.wasmRuntime = wazero.NewRuntimeWithConfig(ctx, rtCfg)
+	wasi_snapshot_preview1.MustInstantiate(ctx, v.wasmRuntime)
+
+	for _, c := range cfgs {
+		timeout := wasmDefaultTimeout
+		if c.Timeout != "" {
+			d, err := time.ParseDuration(c.Timeout)
+			if err != nil {
+				return fmt.Errorf("wasm rule %s: timeout: %w", c.Name, err)
+			}
+			timeout = d
+		}
+		if c.Input != "" && c.Input != "source" && c.Input != "ast" {
+			return fmt.Errorf("wasm rule %s: input must be source or ast, got %q", c.Name, c.Input)
+		}
+
+		bin, err := os.ReadFile(c.Path)
+		if err != nil {
+			return fmt.Errorf("wasm rule %s: %w", c.Name, err)
+		}
+
+		modCfg := wazero.NewModuleConfig().
+			WithName(c.Name).
+			WithStderr(os.Stderr).
+			WithStartFunctions("_initialize")
+
+		compiled, err := v.wasmRuntime.CompileModule(ctx, bin)
+		if err != nil {
+			return fmt.Errorf("wasm rule %s: %w", c.Name, err)
+		}
+
+		rule := &wasmRule{
+			name:     c.Name,
+			withAST:  c.Input == "ast",
+			timeout:  timeout,
+			rt:       v.wasmRuntime,
+			compiled: compiled,
+			cfg:      modCfg,
+		}
+		if err := rule.instantiate(ctx); err != nil {
+			return fmt.Errorf("wasm rule %s: %w", c.Name, err)
+		}
+		v.wasmRules = append(v.wasmRules, rule)
+	}
+
+	return nil
+}
+
+// closeWASMRules releases the runtime and every module it holds.
+func (v *Validator) closeWASMRules(ctx context.Context) {
+	if v.wasmRuntime != nil {
+		_ = v.wasmRuntime.Close(ctx)
+	}
+	v.wasmRuntime = nil
+	v.wasmRules = nil
+}
+
+// instantiate creates the module of r and looks up its exports.
+func (r *wasmRule) instantiate(ctx context.Context) error {
+	mod, err := r.rt.InstantiateModule(ctx, r.compiled, r.cfg)
+	if err != nil {
+		return err
+	}
+	r.mod = mod
+	r.alloc = mod.ExportedFunction("alloc")
+	r.check = mod.ExportedFunction("check")
+	if r.alloc == nil || r.check == nil || mod.Memory() == nil {
+		return fmt.Errorf("module must export memory, alloc and check")
+	}
+	return nil
+}
+
+// run passes one file to the module and decodes its findings. A call that
+// times out or exits closes the module, so it is instantiated again for the
+// next file.
+func (r *wasmRule) run(ctx context.Context, in []byte) ([]wasmFinding, error) {
+	if r.broken {
+		return nil, nil
+	}
+	findings, err := r.call(ctx, in)
+	if err != nil && r.mod.IsClosed() && ctx.Err() == nil {
+		if ierr := r.instantiate(ctx); ierr != nil {
+			r.broken = true
+			return nil, fmt.Errorf("%w; the rule is disabled, instantiating it again failed: %v", err, ierr)
+		}
+	}
+	return findings, err
+}
+
+// call runs one check call of the module, limited by the rule's timeout.
+func (r *wasmRule) call(ctx context.Context, in []byte) ([]wasmFinding, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.alloc.Call(ctx, uint64(len(in)))
+	if err != nil {
+		return nil, err
+	}
+	ptr := uint32(res[0])
+	if !r.mod.Memory().Write(ptr, in) {
+		return nil, fmt.Errorf("input of %d bytes does not fit in module memory", len(in))
+	}
+
+	res, err = r.check.Call(ctx, uint64(ptr), uint64(len(in)))
+	if err != nil {
+		return nil, err
+	}
+	outPtr, outLen := uint32(res[0]>>32), uint32(res[0])
+	if outLen == 0 {
+		return nil, nil
+	}
+	out, ok := r.mod.Memory().Read(outPtr, outLen)
+	if !ok {
+		return nil, fmt.Errorf("result [%d, %d) is outside module memory", outPtr, outPtr+outLen)
+	}
+
+	var findings []wasmFinding
+	if err := json.Unmarshal(out, &findings); err != nil {
+		return nil, fmt.Errorf("decoding result: %w", err)
+	}
+	return findings, nil
+}
+
+func (v *Validator) validateWASMRules(ctx context.Context, path string, fs *token.FileSet, f *ast.File, errs *[]Issue) {
+	if len(v.wasmRules) == 0 {
+		return
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		report(errs, "wasm", token.Position{Filename: path}, "failed reading file for wasm rules")
+		return
+	}
+
+	// Encode each input form once for all the rules asking for it.
+	inputs := make(map[bool][]byte)
+	for _, rule := range v.wasmRules {
+		in, ok := inputs[rule.withAST]
+		if !ok {
+			input := wasmInput{Path: path, Source: string(src)}
+			if rule.withAST {
+				input.AST = wasmTree(fs, f)
+			}
+			if in, err = json.Marshal(input); err != nil {
+				report(errs, "wasm", token.Position{Filename: path}, "%v", err)
+				return
+			}
+			inputs[rule.withAST] = in
+		}
+
+		findings, err := rule.run(ctx, in)
+		if err != nil {
+			report(errs, "wasm:"+rule.name, token.Position{Filename: path}, "wasm rule %s: %v", rule.name, err)
+			continue
+		}
+		for _, finding := range findings {
+			reportSuggestion(errs, "wasm:"+rule.name, token.Position{Filename: path, Line: finding.Line}, fmt.Sprintf("%s (wasm rule %s)", finding.Message, rule.name), finding.Suggestion)
+		}
+	}
+}
+
+// wasmTree converts f to its wasmNode form.
+func wasmTree(fs *token.FileSet, f *ast.File) *wasmNode {
+	var root *wasmNode
+	var stack []*wasmNode
+	ast.Inspect(f, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+
+		start, end := fs.Position(n.Pos()), fs.Position(n.End())
+		node := &wasmNode{
+			Kind:    reflect.TypeOf(n).Elem().Name(),
+			Line:    start.Line,
+			Col:     start.Column,
+			EndLine: end.Line,
+		}
+		switch n := n.(type) {
+		case *ast.Ident:
+			node.Name = n.Name
+		case *ast.BasicLit:
+			node.Value = n.Value
+		case *ast.Comment:
+			node.Value = n.Text
+		case *ast.BinaryExpr:
+			node.Value = n.Op.String()
+		case *ast.UnaryExpr:
+			node.Value = n.Op.String()
+		case *ast.AssignStmt:
+			node.Value = n.Tok.String()
+		case *ast.IncDecStmt:
+			node.Value = n.Tok.String()
+		case *ast.BranchStmt:
+			node.Value = n.Tok.String()
+		case *ast.GenDecl:
+			node.Value = n.Tok.String()
+		}
+
+		if len(stack) == 0 {
+			root = node
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, node)
+		}
+		stack = append(stack, node)
+		return true
+	})
+	return root
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// ANSI escapes used by the styled text report.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// styled reports whether the text report colors findings and shows a
+// source excerpt under each, as set by -color.
+var styled bool
+
+// snippets reports whether the plain text report shows a source excerpt
+// under each finding as well, as set by -snippets.
+var snippets bool
+
+// useStyle decides -color: "auto" styles the report when stdout is a
+// terminal and NO_COLOR is not set.
+func useStyle(mode string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto", "":
+		if _, ok := os.LookupEnv("NO_COLOR"); ok {
+			return false, nil
+		}
+		info, err := os.Stdout.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	}
+	return false, fmt.Errorf("unknown -color %q; want auto, always or never", mode)
+}
+
+// printIssue prints e as a line of the text report, styled when styled is
+// set: the severity color-coded and the offending source line below with a
+// caret under the column, much like rustc and staticcheck.
+func printIssue(w io.Writer, e validator.Issue) {
+	if !styled {
+		fmt.Fprintln(w, " -", e)
+		if line, caret, ok := excerpt(e.Pos); ok && snippets {
+			fmt.Fprintf(w, "%6d | %s\n", e.Pos.Line, line)
+			fmt.Fprintf(w, "%6s | %s\n", "", caret)
+		}
+		return
+	}
+
+	loc := e.Pos.Filename
+	if e.Pos.Line > 0 {
+		loc += fmt.Sprintf(":%d", e.Pos.Line)
+		if e.Pos.Column > 0 {
+			loc += fmt.Sprintf(":%d", e.Pos.Column)
+		}
+	}
+	color := map[validator.Severity]string{
+		validator.SeverityError:   ansiRed,
+		validator.SeverityWarning: ansiYellow,
+		validator.SeverityInfo:    ansiCyan,
+	}[e.Severity]
+	fmt.Fprintf(w, " - %s%s:%s %s%s%s%s", ansiBold, loc, ansiReset, ansiBold, color, e.Severity, ansiReset)
+	if e.ID != "" {
+		fmt.Fprintf(w, " %s[%s]%s", ansiDim, e.ID, ansiReset)
+	}
+	fmt.Fprintf(w, " %s", e.Message)
+	if e.Suggestion != "" {
+		fmt.Fprintf(w, "; %s", e.Suggestion)
+	}
+	if len(e.Also) > 0 {
+		fmt.Fprintf(w, " %s(also reported by %s)%s", ansiDim, strings.Join(e.Also, ", "), ansiReset)
+	}
+	fmt.Fprintln(w)
+
+	if line, caret, ok := excerpt(e.Pos); ok {
+		gutter := fmt.Sprintf("%6d | ", e.Pos.Line)
+		fmt.Fprintf(w, "%s%s%s%s\n", ansiDim, gutter, ansiReset, line)
+		fmt.Fprintf(w, "%s%*s | %s%s%s%s\n", ansiDim, 6, "", ansiReset, color, caret, ansiReset)
+	}
+}
+
+// maxExcerpt is the longest source line shown in an excerpt; longer ones,
+// such as generated data, are left out.
+const maxExcerpt = 300
+
+// sourceLines caches the lines of the files excerpts are taken from.
+var sourceLines = make(map[string][]string)
+
+// excerpt returns the source line at pos and a caret line pointing at its
+// column, keeping the line's tabs so the caret lines up. It reports false
+// when pos has no line or the line cannot be shown.
+func excerpt(pos token.Position) (line, caret string, ok bool) {
+	if pos.Line <= 0 {
+		return "", "", false
+	}
+	lines := fileLines(pos.Filename)
+	if pos.Line > len(lines) {
+		return "", "", false
+	}
+	line = strings.TrimRight(lines[pos.Line-1], "\r")
+	if len(line) > maxExcerpt || strings.TrimSpace(line) == "" {
+		return "", "", false
+	}
+
+	col := pos.Column
+	if col <= 0 {
+		col = len(line) - len(strings.TrimLeft(line, " \t")) + 1
+	}
+	var b strings.Builder
+	for i, r := range line {
+		if i >= col-1 {
+			break
+		}
+		if r == '\t' {
+			b.WriteByte('\t')
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	b.WriteByte('^')
+	return line, b.String(), true
+}
+
+// fileLines returns the lines of the file at path, or nil when it cannot be
+// read, caching them for the next finding in the file.
+func fileLines(path string) []string {
+	lines, cached := sourceLines[path]
+	if !cached {
+		if data, err := os.ReadFile(path); err == nil {
+			lines = strings.Split(string(data), "\n")
+		}
+		sourceLines[path] = lines
+	}
+	return lines
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// The SARIF 2.1.0 subset written by writeSARIF, enough for GitHub code
+// scanning and other dashboards to show findings, rule docs and fixes.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool               sarifTool                `json:"tool"`
+		OriginalURIBaseIDs map[string]sarifArtifact `json:"originalUriBaseIds,omitempty"`
+		Results            []sarifResult            `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID                   string          `json:"id"`
+		Name                 string          `json:"name,omitempty"`
+		ShortDescription     *sarifMessage   `json:"shortDescription,omitempty"`
+		FullDescription      *sarifMessage   `json:"fullDescription,omitempty"`
+		DefaultConfiguration sarifRuleConfig `json:"defaultConfiguration"`
+		Properties           *sarifProps     `json:"properties,omitempty"`
+	}
+	sarifRuleConfig struct {
+		Level string `json:"level"`
+	}
+	sarifProps struct {
+		Tags []string `json:"tags,omitempty"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		RuleIndex int             `json:"ruleIndex"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+		Fixes     []sarifFix      `json:"fixes,omitempty"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifact `json:"artifactLocation"`
+		Region           *sarifRegion  `json:"region,omitempty"`
+	}
+	sarifArtifact struct {
+		URI       string `json:"uri"`
+		URIBaseID string `json:"uriBaseId,omitempty"`
+	}
+	sarifRegion struct {
+		StartLine   int           `json:"startLine,omitempty"`
+		StartColumn int           `json:"startColumn,omitempty"`
+		ByteOffset  *int          `json:"byteOffset,omitempty"`
+		ByteLength  *int          `json:"byteLength,omitempty"`
+		Snippet     *sarifMessage `json:"snippet,omitempty"`
+	}
+	sarifFix struct {
+		Description     sarifMessage          `json:"description"`
+		ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+	}
+	sarifArtifactChange struct {
+		ArtifactLocation sarifArtifact      `json:"artifactLocation"`
+		Replacements     []sarifReplacement `json:"replacements"`
+	}
+	sarifReplacement struct {
+		DeletedRegion   sarifRegion   `json:"deletedRegion"`
+		InsertedContent *sarifMessage `json:"insertedContent,omitempty"`
+	}
+)
+
+// sarifLevel maps a severity to a SARIF result level.
+func sarifLevel(s validator.Severity) string {
+	switch s {
+	case validator.SeverityError:
+		return "error"
+	case validator.SeverityWarning:
+		return "warning"
+	}
+	return "note"
+}
+
+// sarifRuleID is the ID a finding is reported under: the stable rule ID
+// where there is one, so dashboards keep tracking findings across renames.
+func sarifRuleID(e validator.Issue) string {
+	if e.ID != "" {
+		return e.ID
+	}
+	return e.Rule
+}
+
+// sarifSrcRoot is the base of the artifact URIs in SARIF logs: the working
+// directory, in CI the checkout of the repository.
+const sarifSrcRoot = "%SRCROOT%"
+
+// sarifArtifactFor locates path relative to srcRoot, or by its file URI
+// when it lies outside.
+func sarifArtifactFor(path, srcRoot string) sarifArtifact {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return sarifArtifact{URI: filepath.ToSlash(path)}
+	}
+	if rel, err := filepath.Rel(srcRoot, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return sarifArtifact{URI: filepath.ToSlash(rel), URIBaseID: sarifSrcRoot}
+	}
+	return sarifArtifact{URI: fileURI(abs)}
+}
+
+// fileURI returns the file:// URI of the absolute path abs.
+func fileURI(abs string) string {
+	path := filepath.ToSlash(abs)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// sarifPath returns the file an artifact location refers to, relative to
+// the working directory when it is based on sarifSrcRoot.
+func sarifPath(artifact sarifArtifact) string {
+	if u, err := url.Parse(artifact.URI); err == nil && u.Scheme == "file" {
+		path := u.Path
+		// Windows paths, /C:/x.go, lose the slash before the drive letter.
+		if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+			path = path[1:]
+		}
+		return filepath.FromSlash(path)
+	}
+	return filepath.FromSlash(artifact.URI)
+}
+
+// writeSARIF writes issues as a SARIF 2.1.0 log with one run. The driver
+// lists the rules that have findings, with the description, docs, tags and
+// default level of the rule reporting them; automatic fixes become SARIF
+// fixes. Files are located relative to sarifSrcRoot.
+func writeSARIF(w io.Writer, issues []validator.Issue) error {
+	srcRoot, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("writing SARIF report: %w", err)
+	}
+
+	driver := sarifDriver{
+		Name:           "fpvalidator",
+		InformationURI: "https://github.com/ANISH-GOTTAPU/FPVALIDATOR",
+		Rules:          []sarifRule{},
+	}
+	ruleIndex := make(map[string]int)
+	results := make([]sarifResult, 0, len(issues))
+	for _, e := range issues {
+		id := sarifRuleID(e)
+		index, ok := ruleIndex[id]
+		if !ok {
+			rule := sarifRule{
+				ID:                   id,
+				Name:                 e.Rule,
+				DefaultConfiguration: sarifRuleConfig{Level: sarifLevel(validator.DefaultSeverity(e.Rule))},
+			}
+			if r, ok := validator.RuleFor(e.Rule); ok {
+				rule.ShortDescription = &sarifMessage{Text: r.Description()}
+				rule.FullDescription = &sarifMessage{Text: validator.RuleDocs(r)}
+			}
+			if tags := validator.RuleTags(e.Rule); len(tags) > 0 {
+				rule.Properties = &sarifProps{Tags: tags}
+			}
+			index = len(driver.Rules)
+			ruleIndex[id] = index
+			driver.Rules = append(driver.Rules, rule)
+		}
+
+		artifact := sarifArtifactFor(e.Pos.Filename, srcRoot)
+		location := sarifPhysicalLocation{ArtifactLocation: artifact}
+		if e.Pos.Line > 0 {
+			location.Region = &sarifRegion{StartLine: e.Pos.Line, StartColumn: e.Pos.Column}
+			if line, _, ok := excerpt(e.Pos); ok {
+				location.Region.Snippet = &sarifMessage{Text: line}
+			}
+		}
+		result := sarifResult{
+			RuleID:    id,
+			RuleIndex: index,
+			Level:     sarifLevel(e.Severity),
+			Message:   sarifMessage{Text: e.Text()},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		}
+		if len(e.Fix) > 0 {
+			change := sarifArtifactChange{ArtifactLocation: artifact}
+			for _, edit := range e.Fix {
+				offset, length := edit.Start, edit.End-edit.Start
+				replacement := sarifReplacement{DeletedRegion: sarifRegion{ByteOffset: &offset, ByteLength: &length}}
+				if edit.NewText != "" {
+					replacement.InsertedContent = &sarifMessage{Text: edit.NewText}
+				}
+				change.Replacements = append(change.Replacements, replacement)
+			}
+			description := e.Suggestion
+			if description == "" {
+				description = "Apply the fix for " + e.Rule
+			}
+			result.Fixes = []sarifFix{{Description: sarifMessage{Text: description}, ArtifactChanges: []sarifArtifactChange{change}}}
+		}
+		results = append(results, result)
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:               sarifTool{Driver: driver},
+			OriginalURIBaseIDs: map[string]sarifArtifact{sarifSrcRoot: {URI: strings.TrimSuffix(fileURI(srcRoot), "/") + "/"}},
+			Results:            results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(log); err != nil {
+		return fmt.Errorf("writing SARIF report: %w", err)
+	}
+	return nil
+}
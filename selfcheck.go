@@ -0,0 +1,100 @@
+package main
+
+import (
+	"embed"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// selfSource is the validator command's own source, a small known-good tree
+// the configured rules are run over by "validator selfcheck".
+//
+//go:embed *.go
+var selfSource embed.FS
+
+// runSelfcheck implements "validator selfcheck": it checks the config for
+// unknown rules and tags and bad globs and regexes, starts its plugins and
+// WASM rules, and runs the configured rule set over the validator's own
+// source, so a broken setup fails in seconds instead of after a long scan.
+func runSelfcheck(args []string) int {
+	flags := flag.NewFlagSet("selfcheck", flag.ExitOnError)
+	flags.StringVar(configPath, "config", "", "path to a YAML config file")
+	flags.StringVar(profile, "profile", "", "built-in rule profile the config is applied on top of")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: validator selfcheck [-config file] [-profile name]")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+	if err := applyEnv(flags); err != nil {
+		fmt.Println(err)
+		return 2
+	}
+
+	var cfg *validator.Config
+	if *configPath != "" {
+		var err error
+		if cfg, err = validator.LoadConfig(*configPath); err != nil {
+			fmt.Println(err)
+			return 1
+		}
+	}
+	if *profile != "" {
+		var err error
+		if cfg, err = validator.ApplyProfile(cfg, *profile); err != nil {
+			fmt.Println(err)
+			return 1
+		}
+	}
+	if cfg != nil {
+		if problems := validator.CheckConfig(cfg); len(problems) > 0 {
+			fmt.Println("Config check failed:")
+			for _, err := range problems {
+				fmt.Println(" -", err)
+			}
+			return 1
+		}
+	}
+
+	v, err := validator.New(validator.Options{Config: cfg})
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	defer closeValidator(v)
+
+	dir, err := os.MkdirTemp("", "fpvalidator-selfcheck")
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+	if err := os.CopyFS(dir, selfSource); err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	files, _ := fs.Glob(selfSource, "*.go")
+
+	issues, err := selfValidate(v, dir)
+	if err != nil {
+		fmt.Println("Rules failed on the validator's own source:", err)
+		return 1
+	}
+	fmt.Printf("Ran the configured rules over %d files of the validator's own source (%d findings)\n", len(files), len(issues))
+	fmt.Println("Self-check passed ✅")
+	return 0
+}
+
+// selfValidate validates dir and turns a rule panicking into an error.
+func selfValidate(v *validator.Validator, dir string) (issues []validator.Issue, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return v.Validate(filepath.Clean(dir))
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// statsTop is the number of rules and directories the text footer lists;
+// JSON reports carry them all.
+const statsTop = 10
+
+// runStats aggregates the findings of a run for the -stats footer.
+type runStats struct {
+	// Files is the number of .go files checked, 0 when unknown, e.g. for
+	// runs on a daemon.
+	Files       int            `json:"files,omitempty"`
+	Elapsed     string         `json:"elapsed"`
+	Severities  map[string]int `json:"severities"`
+	Rules       map[string]int `json:"rules"`
+	Directories map[string]int `json:"directories"`
+}
+
+// jsonReport is the -format=json report with -stats.
+type jsonReport struct {
+	Findings []jsonIssue `json:"findings"`
+	Stats    runStats    `json:"stats"`
+}
+
+// newRunStats counts issues per severity, rule and directory. v reports the
+// files checked when it can; start is when the run began.
+func newRunStats(issues []validator.Issue, v checker, start time.Time) runStats {
+	s := runStats{
+		Elapsed:     time.Since(start).Round(time.Millisecond).String(),
+		Severities:  make(map[string]int),
+		Rules:       make(map[string]int),
+		Directories: make(map[string]int),
+	}
+	if counter, ok := v.(interface{ FilesChecked() int }); ok {
+		s.Files = counter.FilesChecked()
+	}
+	for _, e := range issues {
+		s.Severities[e.Severity.String()]++
+		s.Rules[e.Rule]++
+		s.Directories[filepath.ToSlash(filepath.Dir(e.Pos.Filename))]++
+	}
+	return s
+}
+
+// printStats prints s as the footer of a report:
+//
+//	Summary: 3 errors, 1 warning in 12 files checked (1.2s)
+//	  by rule: mixed-caps 3, doc-comment 1
+//	  by directory: feature/bgp 4
+func printStats(w io.Writer, s runStats) {
+	var parts []string
+	for _, sev := range []validator.Severity{validator.SeverityError, validator.SeverityWarning, validator.SeverityInfo} {
+		if n := s.Severities[sev.String()]; n > 0 {
+			parts = append(parts, plural(n, sev.String()))
+		}
+	}
+	if len(parts) == 0 {
+		parts = []string{"no findings"}
+	}
+	fmt.Fprintf(w, "\nSummary: %s", strings.Join(parts, ", "))
+	if s.Files > 0 {
+		fmt.Fprintf(w, " in %s checked", plural(s.Files, "file"))
+	}
+	fmt.Fprintf(w, " (%s)\n", s.Elapsed)
+	if len(s.Rules) > 0 {
+		fmt.Fprintf(w, "  by rule: %s\n", topCounts(s.Rules))
+		fmt.Fprintf(w, "  by directory: %s\n", topCounts(s.Directories))
+	}
+}
+
+// topCounts formats the statsTop largest counts, largest first.
+func topCounts(counts map[string]int) string {
+	keys := slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+		return cmp.Or(counts[b]-counts[a], strings.Compare(a, b))
+	})
+	var parts []string
+	for _, k := range keys[:min(len(keys), statsTop)] {
+		parts = append(parts, fmt.Sprintf("%s %d", k, counts[k]))
+	}
+	if len(keys) > statsTop {
+		parts = append(parts, fmt.Sprintf("and %d more", len(keys)-statsTop))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeJSONStats writes issues and s as one JSON object.
+func writeJSONStats(w io.Writer, issues []validator.Issue, s runStats) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(jsonReport{Findings: jsonIssues(issues), Stats: s}); err != nil {
+		return fmt.Errorf("writing JSON report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// runSuppress implements "validator suppress": it inserts an ignore
+// directive above every current finding of one rule, so the rule can be
+// enabled while its existing violations are fixed one change at a time.
+// Review the result with git diff.
+func runSuppress(args []string) int {
+	flags := flag.NewFlagSet("suppress", flag.ExitOnError)
+	rule := flags.String("rule", "", "rule whose findings are suppressed")
+	reason := flags.String("reason", "TODO(b/XXXXXXXXX): fix and remove this suppression", "text recorded after the directive")
+	flags.StringVar(configPath, "config", "", "path to a YAML config file")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: validator suppress -rule=<rule> [flags] <path>...")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+	if err := applyEnv(flags); err != nil {
+		fmt.Println(err)
+		return 2
+	}
+
+	roots := flags.Args()
+	if *rule == "" || (len(roots) == 0 && *configPath == "") {
+		flags.Usage()
+		return 2
+	}
+
+	var cfg *validator.Config
+	if *configPath != "" {
+		var err error
+		if cfg, err = validator.LoadConfig(*configPath); err != nil {
+			fmt.Println(err)
+			return 1
+		}
+		roots = append(cfg.Roots, roots...)
+	}
+
+	// Folded findings must be seen under their own rule.
+	v, err := validator.New(validator.Options{Config: cfg, KeepDuplicates: true})
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	defer closeValidator(v)
+
+	targets := make(map[string][]int)
+	for _, root := range roots {
+		errs, err := v.Validate(root)
+		if err != nil {
+			fmt.Println(err)
+			return 1
+		}
+		for _, d := range errs {
+			if (d.Rule == *rule || strings.EqualFold(d.ID, *rule)) && d.Pos.Line > 0 && !containsInt(targets[d.Pos.Filename], d.Pos.Line) {
+				targets[d.Pos.Filename] = append(targets[d.Pos.Filename], d.Pos.Line)
+			}
+		}
+	}
+
+	files := make([]string, 0, len(targets))
+	for file := range targets {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	total := 0
+	for _, file := range files {
+		if err := validator.InsertSuppressions(file, targets[file], *rule, *reason); err != nil {
+			fmt.Println(err)
+			return 1
+		}
+		fmt.Printf("%s: suppressed %d findings of %s\n", file, len(targets[file]), *rule)
+		total += len(targets[file])
+	}
+	fmt.Printf("Suppressed %d findings of %s in %d files\n", total, *rule, len(files))
+	return 0
+}
+
+// containsInt reports whether list contains n.
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// writeTAP writes issues as a TAP version 13 stream with one test point per
+// rule: the registered rules in the order they run, then the plugin, WASM
+// and custom rules that reported. A rule with findings is "not ok" and
+// lists them in a YAML block; when none of them is an error the point is
+// marked TODO, so harnesses do not count warnings as failures.
+func writeTAP(w io.Writer, issues []validator.Issue) error {
+	byRule := make(map[string][]validator.Issue)
+	var rules []string
+	for _, r := range validator.Rules() {
+		rules = append(rules, r.ID())
+		byRule[r.ID()] = nil
+	}
+	for _, e := range issues {
+		if _, ok := byRule[e.Rule]; !ok {
+			rules = append(rules, e.Rule)
+		}
+		byRule[e.Rule] = append(byRule[e.Rule], e)
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "TAP version 13")
+	fmt.Fprintf(b, "1..%d\n", len(rules))
+	for i, rule := range rules {
+		name := rule
+		if id := validator.RuleID(rule); id != "" {
+			name = id + " " + rule
+		}
+		findings := byRule[rule]
+		if len(findings) == 0 {
+			fmt.Fprintf(b, "ok %d - %s\n", i+1, name)
+			continue
+		}
+		worst := validator.SeverityInfo
+		for _, e := range findings {
+			worst = max(worst, e.Severity)
+		}
+		directive := ""
+		if worst < validator.SeverityError {
+			directive = " # TODO " + worst.String() + "s only"
+		}
+		fmt.Fprintf(b, "not ok %d - %s%s\n", i+1, name, directive)
+		fmt.Fprintln(b, "  ---")
+		fmt.Fprintf(b, "  severity: %s\n", worst)
+		fmt.Fprintln(b, "  findings:")
+		for _, e := range findings {
+			fmt.Fprintf(b, "    - %s\n", strconv.Quote(e.String()))
+		}
+		fmt.Fprintln(b, "  ...")
+	}
+	return flushReport(b, "TAP")
+}
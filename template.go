@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/ANISH-GOTTAPU/FPVALIDATOR/pkg/validator"
+)
+
+// outputTemplate is the parsed -template, executed once per finding by
+// -format=template.
+var outputTemplate *template.Template
+
+// templateIssue is the data -template is executed with, e.g.
+// '{{.File}}:{{.Line}} {{.RuleID}} {{.Message}}'.
+type templateIssue struct {
+	File         string
+	Line, Column int
+	Rule, RuleID string
+	Severity     string
+	Message      string
+	Suggestion   string
+	// Text is the message and suggestion as the text report prints them.
+	Text    string
+	Also    []string
+	Excerpt string
+}
+
+// parseTemplate parses the -template flag into outputTemplate. An empty
+// flag is only an error with -format=template.
+func parseTemplate(text, format string) error {
+	if text == "" {
+		if format == "template" {
+			return fmt.Errorf("-format=template needs -template, e.g. -template='{{.File}}:{{.Line}} {{.RuleID}} {{.Message}}'")
+		}
+		return nil
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	t, err := template.New("template").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid -template: %w", err)
+	}
+	// Unknown fields only show when the template runs, so run it once now.
+	if err := t.Execute(io.Discard, templateIssue{}); err != nil {
+		return fmt.Errorf("invalid -template: %w", err)
+	}
+	outputTemplate = t
+	return nil
+}
+
+// writeTemplate writes each finding with -template, one per line unless the
+// template says otherwise.
+func writeTemplate(w io.Writer, issues []validator.Issue) error {
+	b := bufio.NewWriter(w)
+	for _, e := range issues {
+		line, _, _ := excerpt(e.Pos)
+		data := templateIssue{
+			File:       e.Pos.Filename,
+			Line:       e.Pos.Line,
+			Column:     e.Pos.Column,
+			Rule:       e.Rule,
+			RuleID:     e.ID,
+			Severity:   e.Severity.String(),
+			Message:    e.Message,
+			Suggestion: e.Suggestion,
+			Text:       e.Text(),
+			Also:       e.Also,
+			Excerpt:    line,
+		}
+		if err := outputTemplate.Execute(b, data); err != nil {
+			return fmt.Errorf("writing template report: %w", err)
+		}
+	}
+	return flushReport(b, "template")
+}